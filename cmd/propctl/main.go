@@ -0,0 +1,495 @@
+// Command propctl is a command line tool for manage properties files.
+//
+// All commands read from stdin and write to stdout by default. eg:
+//
+//	cat app.properties | propctl get db.url
+//	propctl set -f app.properties -w db.url=jdbc:mysql://localhost/db
+//	propctl convert -f app.properties --to yaml
+//
+// The ENC(...) values will be decrypted by the key from the env PROPCTL_SECRET_KEY.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/gookit/properties"
+	"github.com/gookit/properties/tomlconv"
+	"github.com/gookit/properties/yamlconv"
+)
+
+// errQuiet exit with code 1 and not print error message
+var errQuiet = errors.New("quiet error")
+
+type command struct {
+	usage string
+	run   func(c *cli, args []string) error
+}
+
+var commands = map[string]command{
+	"get":     {"get [-f file] KEY", runGet},
+	"set":     {"set [-f file] [-w] KEY=VALUE...", runSet},
+	"del":     {"del [-f file] [-w] KEY...", runDel},
+	"fmt":     {"fmt [-f file] [-w] [--compact] [--align] [--sort]", runFmt},
+	"lint":    {"lint [-f file] [--disable RULES] [--key-pattern REGEXP]", runLint},
+	"convert": {"convert [-f file] [--from FORMAT] --to FORMAT", runConvert},
+	"diff":    {"diff FILE_A FILE_B", runDiff},
+	"resolve": {"resolve [-f file] [--env-prefix PREFIX] [--mask]", runResolve},
+	"encrypt": {"encrypt [-f file] [-w] KEY... | encrypt --value TEXT", runEncrypt},
+}
+
+var commandNames = []string{"get", "set", "del", "fmt", "lint", "convert", "diff", "resolve", "encrypt"}
+
+// secretKeyEnv the env name of the passphrase for encrypt and decrypt the ENC(...) values.
+const secretKeyEnv = "PROPCTL_SECRET_KEY"
+
+type cli struct {
+	in     io.Reader
+	out    io.Writer
+	errOut io.Writer
+
+	// input file, "-" is stdin
+	file string
+	// write the result back to the file
+	write bool
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+func run(args []string, in io.Reader, out, errOut io.Writer) int {
+	c := &cli{in: in, out: out, errOut: errOut}
+	if len(args) == 0 || args[0] == "-h" || args[0] == "--help" || args[0] == "help" {
+		c.usage()
+		if len(args) == 0 {
+			return 2
+		}
+		return 0
+	}
+
+	cmd, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(errOut, "propctl: unknown command %q\n", args[0])
+		c.usage()
+		return 2
+	}
+
+	if err := cmd.run(c, args[1:]); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		if !errors.Is(err, errQuiet) {
+			fmt.Fprintln(errOut, "propctl:", err)
+		}
+		return 1
+	}
+	return 0
+}
+
+func (c *cli) usage() {
+	fmt.Fprintln(c.errOut, "Usage: propctl COMMAND [options] [args]")
+	fmt.Fprintln(c.errOut, "\nCommands:")
+	for _, name := range commandNames {
+		fmt.Fprintln(c.errOut, "  propctl "+commands[name].usage)
+	}
+}
+
+// newFlags create flag set with common options. withWrite will add the -w option.
+func (c *cli) newFlags(name string, withWrite bool) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(c.errOut)
+	fs.StringVar(&c.file, "f", "-", "input file, `-` is stdin")
+	if withWrite {
+		fs.BoolVar(&c.write, "w", false, "write result to the input file instead of stdout")
+	}
+	return fs
+}
+
+func (c *cli) readFile(file string) ([]byte, error) {
+	if file == "-" {
+		return io.ReadAll(c.in)
+	}
+	return os.ReadFile(file)
+}
+
+func (c *cli) readInput() ([]byte, error) {
+	if c.write && c.file == "-" {
+		return nil, errors.New("the -w option requires an input file by -f")
+	}
+	return c.readFile(c.file)
+}
+
+func (c *cli) parse(src []byte, optFns ...properties.OpFunc) (*properties.Parser, error) {
+	// decrypt the ENC(...) values on the secret key is provided
+	if os.Getenv(secretKeyEnv) != "" {
+		cipher, err := c.cipher()
+		if err != nil {
+			return nil, err
+		}
+		optFns = append(optFns, properties.WithDecryptor(cipher))
+	}
+
+	p := properties.NewParser(optFns...)
+	if strings.TrimSpace(string(src)) == "" {
+		return p, nil
+	}
+	return p, p.ParseBytes(src)
+}
+
+// cipher create the AES-GCM cipher by the passphrase from env PROPCTL_SECRET_KEY.
+func (c *cli) cipher() (*properties.PassphraseAES, error) {
+	passphrase := os.Getenv(secretKeyEnv)
+	if passphrase == "" {
+		return nil, fmt.Errorf("the env %s is required for encrypt and decrypt values", secretKeyEnv)
+	}
+	return properties.NewPassphraseAES(passphrase), nil
+}
+
+// output the result to stdout or write back to the input file.
+func (c *cli) output(bs []byte) error {
+	if c.write {
+		return os.WriteFile(c.file, bs, 0644)
+	}
+	_, err := c.out.Write(bs)
+	return err
+}
+
+func runGet(c *cli, args []string) error {
+	fs := c.newFlags("get", false)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return errors.New("get: requires one KEY argument")
+	}
+
+	src, err := c.readInput()
+	if err != nil {
+		return err
+	}
+	p, err := c.parse(src)
+	if err != nil {
+		return err
+	}
+
+	key := fs.Arg(0)
+	if val, ok := p.SMap()[key]; ok {
+		_, err = fmt.Fprintln(c.out, val)
+		return err
+	}
+
+	// sub data of the key
+	val, ok := p.Value(key)
+	if !ok {
+		return fmt.Errorf("get: key %q not found", key)
+	}
+
+	bs, err := properties.Encode(map[string]any{key: val})
+	if err != nil {
+		return err
+	}
+	return c.output(bs)
+}
+
+func runSet(c *cli, args []string) error {
+	fs := c.newFlags("set", true)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return errors.New("set: requires KEY=VALUE arguments")
+	}
+
+	src, err := c.readInput()
+	if err != nil {
+		return err
+	}
+
+	for _, arg := range fs.Args() {
+		key, val, ok := strings.Cut(arg, "=")
+		if key = strings.TrimSpace(key); !ok || key == "" {
+			return fmt.Errorf("set: invalid argument %q, should be KEY=VALUE", arg)
+		}
+
+		if src, err = properties.SetKeyValue(src, key, val); err != nil {
+			return err
+		}
+	}
+	return c.output(src)
+}
+
+func runDel(c *cli, args []string) error {
+	fs := c.newFlags("del", true)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return errors.New("del: requires KEY arguments")
+	}
+
+	src, err := c.readInput()
+	if err != nil {
+		return err
+	}
+
+	for _, key := range fs.Args() {
+		if src, err = properties.DeleteKey(src, key); err != nil {
+			if errors.Is(err, properties.ErrNotFound) {
+				return fmt.Errorf("del: key %q not found", key)
+			}
+			return err
+		}
+	}
+	return c.output(src)
+}
+
+func runFmt(c *cli, args []string) error {
+	opts := &properties.FormatOptions{}
+	fs := c.newFlags("fmt", true)
+	fs.BoolVar(&opts.Compact, "compact", false, "use separator \"=\" instead of \" = \"")
+	fs.BoolVar(&opts.Align, "align", false, "align the values within a block")
+	fs.BoolVar(&opts.SortKeys, "sort", false, "sort the keys within a block")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	src, err := c.readInput()
+	if err != nil {
+		return err
+	}
+
+	bs, err := properties.Format(src, opts)
+	if err != nil {
+		return err
+	}
+	return c.output(bs)
+}
+
+func runLint(c *cli, args []string) error {
+	var disable, keyPattern string
+	fs := c.newFlags("lint", false)
+	fs.StringVar(&disable, "disable", "", "disable the rules, multi split by comma. eg: non-ascii,key-naming")
+	fs.StringVar(&keyPattern, "key-pattern", "", "custom regexp pattern for check the key name")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	lt := properties.NewLinter()
+	if disable != "" {
+		for _, rule := range strings.Split(disable, ",") {
+			rule = strings.TrimSpace(rule)
+			if !isLintRule(rule) {
+				return fmt.Errorf("lint: unknown rule %q, allow: %s", rule, strings.Join(properties.LintRules, ", "))
+			}
+			lt.Disable(rule)
+		}
+	}
+
+	if keyPattern != "" {
+		re, err := regexp.Compile(keyPattern)
+		if err != nil {
+			return fmt.Errorf("lint: invalid key pattern: %w", err)
+		}
+		lt.KeyPattern = re
+	}
+
+	src, err := c.readInput()
+	if err != nil {
+		return err
+	}
+
+	issues := lt.Lint(src)
+	for _, issue := range issues {
+		fmt.Fprintf(c.out, "%s:%d: %s (%s)\n", c.file, issue.Line, issue.Message, issue.Rule)
+	}
+
+	if len(issues) > 0 {
+		return errQuiet
+	}
+	return nil
+}
+
+func isLintRule(name string) bool {
+	for _, rule := range properties.LintRules {
+		if rule == name {
+			return true
+		}
+	}
+	return false
+}
+
+func runConvert(c *cli, args []string) error {
+	var from, to, prefix string
+	var flat bool
+
+	fs := c.newFlags("convert", false)
+	fs.StringVar(&from, "from", "properties", "input format: properties, json, yaml, toml, ini, env")
+	fs.StringVar(&to, "to", "", "output format: properties, json, yaml, toml, ini, env")
+	fs.BoolVar(&flat, "flat", false, "use flat keys on output json")
+	fs.StringVar(&prefix, "prefix", "", "env var name prefix for the env format")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if to == "" {
+		return errors.New("convert: the --to option is required")
+	}
+
+	src, err := c.readInput()
+	if err != nil {
+		return err
+	}
+
+	var p *properties.Parser
+	envCfg := &properties.DotenvConfig{Prefix: prefix}
+	switch from {
+	case "properties":
+		p, err = c.parse(src)
+	case "json":
+		p, err = properties.FromJSON(src)
+	case "yaml", "yml":
+		p, err = yamlconv.FromYAML(src)
+	case "toml":
+		p, err = tomlconv.FromTOML(src)
+	case "ini":
+		p, err = properties.FromINI(src)
+	case "env", "dotenv":
+		p, err = properties.FromDotenv(src, envCfg)
+	default:
+		return fmt.Errorf("convert: unsupported input format %q", from)
+	}
+	if err != nil {
+		return err
+	}
+
+	var bs []byte
+	switch to {
+	case "properties":
+		bs, err = properties.NewEncoder().Encode(p)
+	case "json":
+		if bs, err = properties.ToJSON(p, flat); err == nil {
+			bs = append(bs, '\n')
+		}
+	case "yaml", "yml":
+		bs, err = yamlconv.ToYAML(p)
+	case "toml":
+		bs, err = tomlconv.ToTOML(p)
+	case "ini":
+		bs, err = properties.ToINI(p)
+	case "env", "dotenv":
+		bs, err = properties.ToDotenv(p, envCfg)
+	default:
+		return fmt.Errorf("convert: unsupported output format %q", to)
+	}
+	if err != nil {
+		return err
+	}
+	return c.output(bs)
+}
+
+func runDiff(c *cli, args []string) error {
+	fs := flag.NewFlagSet("diff", flag.ContinueOnError)
+	fs.SetOutput(c.errOut)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 2 {
+		return errors.New("diff: requires two file arguments")
+	}
+
+	ps := make([]*properties.Parser, 2)
+	for i, file := range fs.Args() {
+		src, err := c.readFile(file)
+		if err != nil {
+			return err
+		}
+		if ps[i], err = c.parse(src); err != nil {
+			return fmt.Errorf("%s: %w", file, err)
+		}
+	}
+
+	dr := properties.Diff(ps[0], ps[1])
+	if dr.IsEmpty() {
+		return nil
+	}
+
+	fmt.Fprint(c.out, dr.String())
+	// has differences, like the diff command
+	return errQuiet
+}
+
+func runResolve(c *cli, args []string) error {
+	var envPrefix string
+	var mask bool
+	fs := c.newFlags("resolve", false)
+	fs.StringVar(&envPrefix, "env-prefix", "", "overlay the env vars with the prefix onto keys")
+	fs.BoolVar(&mask, "mask", false, "mask the values of secret keys. eg: password, token")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	src, err := c.readInput()
+	if err != nil {
+		return err
+	}
+
+	p, err := c.parse(src, properties.ParseEnv, properties.WithEnvOverlay(envPrefix))
+	if err != nil {
+		return err
+	}
+
+	// encode the parser for keep the decrypted values as ENC(...)
+	enc := properties.NewEncoder()
+	enc.MaskSecrets = mask
+	bs, err := enc.Encode(p)
+	if err != nil {
+		return err
+	}
+	return c.output(bs)
+}
+
+func runEncrypt(c *cli, args []string) error {
+	var value string
+	fs := c.newFlags("encrypt", true)
+	fs.StringVar(&value, "value", "", "encrypt the text and print the ENC(...) value")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if value == "" && fs.NArg() == 0 {
+		return errors.New("encrypt: requires KEY arguments or the --value option")
+	}
+
+	cipher, err := c.cipher()
+	if err != nil {
+		return err
+	}
+
+	if value != "" {
+		enc, err := properties.EncryptValue(cipher, value)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(c.out, enc)
+		return err
+	}
+
+	src, err := c.readInput()
+	if err != nil {
+		return err
+	}
+
+	for _, key := range fs.Args() {
+		if src, err = properties.EncryptKey(src, key, cipher); err != nil {
+			if errors.Is(err, properties.ErrNotFound) {
+				return fmt.Errorf("encrypt: key %q not found", key)
+			}
+			return err
+		}
+	}
+	return c.output(src)
+}
@@ -0,0 +1,219 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gookit/goutil/testutil/assert"
+)
+
+func runCmd(stdin string, args ...string) (code int, out, errOut string) {
+	var ob, eb bytes.Buffer
+	code = run(args, strings.NewReader(stdin), &ob, &eb)
+	return code, ob.String(), eb.String()
+}
+
+func writeTemp(t *testing.T, name, text string) string {
+	file := filepath.Join(t.TempDir(), name)
+	assert.NoErr(t, os.WriteFile(file, []byte(text), 0644))
+	return file
+}
+
+var testSrc = `# database
+db.host = localhost
+db.port = 3306
+
+name = inhere
+tags[0] = a
+tags[1] = b
+`
+
+func TestRun_usage(t *testing.T) {
+	code, _, eo := runCmd("")
+	assert.Eq(t, 2, code)
+	assert.StrContains(t, eo, "Usage: propctl")
+
+	code, _, eo = runCmd("", "not-exist")
+	assert.Eq(t, 2, code)
+	assert.StrContains(t, eo, `unknown command "not-exist"`)
+}
+
+func TestRun_get(t *testing.T) {
+	code, out, _ := runCmd(testSrc, "get", "db.port")
+	assert.Eq(t, 0, code)
+	assert.Eq(t, "3306\n", out)
+
+	code, out, _ = runCmd(testSrc, "get", "db")
+	assert.Eq(t, 0, code)
+	assert.StrContains(t, out, "db.host=localhost")
+	assert.StrContains(t, out, "db.port=3306")
+
+	code, _, eo := runCmd(testSrc, "get", "not-exist")
+	assert.Eq(t, 1, code)
+	assert.StrContains(t, eo, `key "not-exist" not found`)
+}
+
+func TestRun_set_del(t *testing.T) {
+	code, out, _ := runCmd(testSrc, "set", "db.port=3307", "age=23")
+	assert.Eq(t, 0, code)
+	assert.StrContains(t, out, "# database\ndb.host = localhost\ndb.port = 3307\n")
+	assert.StrContains(t, out, "tags[1] = b\nage = 23\n")
+
+	code, out, _ = runCmd(testSrc, "del", "db.host")
+	assert.Eq(t, 0, code)
+	assert.Eq(t, strings.Replace(testSrc, "# database\ndb.host = localhost\n", "", 1), out)
+
+	code, _, eo := runCmd(testSrc, "del", "not-exist")
+	assert.Eq(t, 1, code)
+	assert.StrContains(t, eo, `key "not-exist" not found`)
+
+	// write back to file
+	file := writeTemp(t, "app.properties", testSrc)
+	code, out, _ = runCmd("", "set", "-f", file, "-w", "name=tom")
+	assert.Eq(t, 0, code)
+	assert.Eq(t, "", out)
+
+	bs, err := os.ReadFile(file)
+	assert.NoErr(t, err)
+	assert.Eq(t, strings.Replace(testSrc, "inhere", "tom", 1), string(bs))
+
+	code, _, eo = runCmd(testSrc, "set", "-w", "name=tom")
+	assert.Eq(t, 1, code)
+	assert.StrContains(t, eo, "requires an input file")
+}
+
+func TestRun_fmt_lint(t *testing.T) {
+	code, out, _ := runCmd("# comments\nname=inhere\nage = 23\n\n\n", "fmt")
+	assert.Eq(t, 0, code)
+	assert.Eq(t, "# comments\nname = inhere\nage = 23\n", out)
+
+	code, out, _ = runCmd("name=inhere\nage = 23\n", "fmt", "--compact", "--align", "--sort")
+	assert.Eq(t, 0, code)
+	assert.Eq(t, "age =23\nname=inhere\n", out)
+
+	code, out, _ = runCmd(testSrc, "lint")
+	assert.Eq(t, 0, code)
+	assert.Eq(t, "", out)
+
+	code, out, _ = runCmd("name = '''abc\n", "lint")
+	assert.Eq(t, 1, code)
+	assert.Eq(t, "-:1: multi line value of key \"name\" is not closed (unclosed-block)\n", out)
+
+	src := "name = inhere\nname=tom\n"
+	code, out, _ = runCmd(src, "lint")
+	assert.Eq(t, 1, code)
+	assert.StrContains(t, out, "-:2: duplicate key \"name\", first defined at line 1 (duplicate-key)\n")
+	assert.StrContains(t, out, "(mixed-separator)")
+
+	code, out, _ = runCmd(src, "lint", "--disable", "duplicate-key, mixed-separator")
+	assert.Eq(t, 0, code)
+	assert.Eq(t, "", out)
+
+	code, _, eo := runCmd(src, "lint", "--disable", "not-exist")
+	assert.Eq(t, 1, code)
+	assert.StrContains(t, eo, `unknown rule "not-exist"`)
+
+	code, out, _ = runCmd("appName = a\n", "lint", "--key-pattern", "^[a-z.]+$")
+	assert.Eq(t, 1, code)
+	assert.StrContains(t, out, "(key-naming)")
+}
+
+func TestRun_convert(t *testing.T) {
+	code, out, _ := runCmd(testSrc, "convert", "--to", "json")
+	assert.Eq(t, 0, code)
+	assert.StrContains(t, out, `"host": "localhost"`)
+
+	code, out, _ = runCmd(testSrc, "convert", "--to", "yaml")
+	assert.Eq(t, 0, code)
+	assert.StrContains(t, out, "db:\n  host: localhost\n")
+
+	code, out, _ = runCmd(testSrc, "convert", "--to", "env", "--prefix", "APP_")
+	assert.Eq(t, 0, code)
+	assert.StrContains(t, out, "APP_DB_HOST=localhost")
+
+	code, out, _ = runCmd(`{"db": {"host": "localhost"}}`, "convert", "--from", "json", "--to", "properties")
+	assert.Eq(t, 0, code)
+	assert.StrContains(t, out, "db.host=localhost")
+
+	code, _, eo := runCmd(testSrc, "convert")
+	assert.Eq(t, 1, code)
+	assert.StrContains(t, eo, "--to option is required")
+
+	code, _, eo = runCmd(testSrc, "convert", "--to", "xml")
+	assert.Eq(t, 1, code)
+	assert.StrContains(t, eo, `unsupported output format "xml"`)
+}
+
+func TestRun_diff(t *testing.T) {
+	fa := writeTemp(t, "a.properties", testSrc)
+	code, out, _ := runCmd(testSrc, "diff", fa, "-")
+	assert.Eq(t, 0, code)
+	assert.Eq(t, "", out)
+
+	code, out, _ = runCmd("name = tom\nage = 23\n", "diff", fa, "-")
+	assert.Eq(t, 1, code)
+	assert.StrContains(t, out, "+ age = 23")
+	assert.StrContains(t, out, "- db.host = localhost")
+	assert.StrContains(t, out, "~ name = inhere -> tom")
+}
+
+func TestRun_resolve(t *testing.T) {
+	t.Setenv("PROPCTL_TEST_HOST", "127.0.0.1")
+	t.Setenv("APP_NAME", "tom")
+
+	src := "db.host = ${PROPCTL_TEST_HOST}\nname = inhere\n"
+	code, out, _ := runCmd(src, "resolve", "--env-prefix", "APP_")
+	assert.Eq(t, 0, code)
+	assert.StrContains(t, out, "db.host=127.0.0.1")
+	assert.StrContains(t, out, "name=tom")
+}
+
+func TestRun_encrypt(t *testing.T) {
+	src := "spring.redis.host = localhost\nspring.redis.password = pwd234\n"
+	t.Setenv("PROPCTL_SECRET_KEY", "")
+	code, _, errOut := runCmd(src, "encrypt", "spring.redis.password")
+	assert.Eq(t, 1, code)
+	assert.StrContains(t, errOut, "PROPCTL_SECRET_KEY is required")
+
+	t.Setenv("PROPCTL_SECRET_KEY", "my-secret")
+	code, out, _ := runCmd("", "encrypt", "--value", "pwd234")
+	assert.Eq(t, 0, code)
+	assert.True(t, strings.HasPrefix(out, "ENC("))
+
+	file := writeTemp(t, "app.properties", src)
+	code, _, _ = runCmd("", "encrypt", "-f", file, "-w", "spring.redis.password")
+	assert.Eq(t, 0, code)
+
+	bs, err := os.ReadFile(file)
+	assert.NoErr(t, err)
+	assert.StrContains(t, string(bs), "spring.redis.password = ENC(")
+	assert.NotContains(t, string(bs), "pwd234")
+
+	// decrypt on parse
+	code, out, _ = runCmd("", "get", "-f", file, "spring.redis.password")
+	assert.Eq(t, 0, code)
+	assert.Eq(t, "pwd234\n", out)
+
+	code, out, _ = runCmd("", "resolve", "-f", file, "--mask")
+	assert.Eq(t, 0, code)
+	assert.Eq(t, "spring.redis.host=localhost\nspring.redis.password=******\n", out)
+
+	// decrypted value of not secret key
+	enc, err := os.ReadFile(file)
+	assert.NoErr(t, err)
+	file = writeTemp(t, "conn.properties", strings.Replace(string(enc), "spring.redis.password", "db.conn", 1))
+	code, out, _ = runCmd("", "resolve", "-f", file, "--mask")
+	assert.Eq(t, 0, code)
+	assert.Eq(t, "db.conn=******\nspring.redis.host=localhost\n", out)
+
+	code, out, _ = runCmd("", "resolve", "-f", file)
+	assert.Eq(t, 0, code)
+	assert.StrContains(t, out, "db.conn=ENC(")
+
+	code, _, errOut = runCmd(src, "encrypt", "not-exist")
+	assert.Eq(t, 1, code)
+	assert.StrContains(t, errOut, `key "not-exist" not found`)
+}
@@ -0,0 +1,199 @@
+package properties
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// ToJSON convert the parsed data to JSON. flat=true will use the flat keys. eg: {"db.url": "..."}
+func ToJSON(p *Parser, flat bool) ([]byte, error) {
+	if flat {
+		return json.MarshalIndent(p.smap, "", "  ")
+	}
+	return json.MarshalIndent(p.Data, "", "  ")
+}
+
+// FromJSON convert JSON object to properties Parser. support nested and flat keys.
+func FromJSON(bs []byte, optFns ...OpFunc) (*Parser, error) {
+	dec := json.NewDecoder(bytes.NewReader(bs))
+	// keep the number string
+	dec.UseNumber()
+
+	data := make(map[string]any)
+	if err := dec.Decode(&data); err != nil {
+		return nil, err
+	}
+	return FromMap(data, optFns...)
+}
+
+// FromMap flatten the nested data to properties keys, then collect to Parser.
+// It is useful for convert other formats to properties. eg: YAML, TOML
+//
+// Slices will be as indexed keys, like Spring. eg: list[0]=a
+// The nil value will be as empty string.
+func FromMap(data map[string]any, optFns ...OpFunc) (*Parser, error) {
+	p := NewParser(optFns...)
+	flattenData("", data, func(key, val string) {
+		if p.err == nil {
+			p.overlayValue(key, val)
+		}
+	})
+	return p, p.err
+}
+
+// flattenData walk the nested data, call fn for each scalar value. nil value will be as empty string.
+func flattenData(path string, val any, fn func(key, val string)) {
+	if mp, ok := asMap(val); ok {
+		for _, key := range sortedKeys(mp) {
+			if path != "" {
+				flattenData(path+"."+key, mp[key], fn)
+			} else {
+				flattenData(key, mp[key], fn)
+			}
+		}
+		return
+	}
+
+	if ls, ok := asList(val); ok {
+		for i, item := range ls {
+			flattenData(path+"["+strconv.Itoa(i)+"]", item, fn)
+		}
+		return
+	}
+
+	fn(path, scalarString(val))
+}
+
+// TypedData copy the Data for marshal to other formats. eg: YAML, TOML
+//
+// The number and bool string will be as typed value, but the quoted value is kept as string.
+// eg: port=8080 -> 8080, flag="true" -> "true"
+func (p *Parser) TypedData() map[string]any {
+	return p.typedValue("", map[string]any(p.Data)).(map[string]any)
+}
+
+func (p *Parser) typedValue(path string, val any) any {
+	// quoted value is kept as string
+	if e, ok := p.entries[path]; ok && e.Quoted() {
+		return val
+	}
+
+	if mp, ok := asMap(val); ok {
+		nmp := make(map[string]any, len(mp))
+		for key, item := range mp {
+			if path != "" {
+				nmp[key] = p.typedValue(path+"."+key, item)
+			} else {
+				nmp[key] = p.typedValue(key, item)
+			}
+		}
+		return nmp
+	}
+
+	if ls, ok := asList(val); ok {
+		nls := make([]any, len(ls))
+		for i, item := range ls {
+			nls[i] = p.typedValue(path+"["+strconv.Itoa(i)+"]", item)
+		}
+		return nls
+	}
+
+	if str, ok := val.(string); ok {
+		// keep as number, bool on the string is not changed. eg: "010", "True" are kept as string
+		typed := inferValue(str)
+		if _, isStr := typed.(string); !isStr && typed != nil && scalarString(typed) == str {
+			return typed
+		}
+	}
+	return val
+}
+
+func sortedKeys(mp map[string]any) []string {
+	keys := make([]string, 0, len(mp))
+	for key := range mp {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// asMap convert the map value to map[string]any
+func asMap(val any) (map[string]any, bool) {
+	switch typVal := val.(type) {
+	case map[string]any:
+		return typVal, true
+	case map[string]string:
+		mp := make(map[string]any, len(typVal))
+		for k, v := range typVal {
+			mp[k] = v
+		}
+		return mp, true
+	case map[any]any:
+		mp := make(map[string]any, len(typVal))
+		for k, v := range typVal {
+			mp[fmt.Sprint(k)] = v
+		}
+		return mp, true
+	}
+	return nil, false
+}
+
+// asList convert the slice value to []any
+func asList(val any) ([]any, bool) {
+	switch typVal := val.(type) {
+	case []any:
+		return typVal, true
+	case []string:
+		ls := make([]any, len(typVal))
+		for i, v := range typVal {
+			ls[i] = v
+		}
+		return ls, true
+	case []map[string]any:
+		ls := make([]any, len(typVal))
+		for i, v := range typVal {
+			ls[i] = v
+		}
+		return ls, true
+	}
+	return nil, false
+}
+
+// scalarString convert the scalar value to string
+func scalarString(val any) string {
+	switch typVal := val.(type) {
+	case string:
+		return typVal
+	case nil:
+		return ""
+	case bool:
+		return strconv.FormatBool(typVal)
+	case int64:
+		return strconv.FormatInt(typVal, 10)
+	case float64:
+		return strconv.FormatFloat(typVal, 'f', -1, 64)
+	case json.Number:
+		return typVal.String()
+	case time.Time:
+		return timeString(typVal)
+	}
+	return fmt.Sprint(val)
+}
+
+// timeString format the time value, keep the format of TOML local date and time.
+func timeString(t time.Time) string {
+	// the zone names of the TOML local datetime, date and time. see github.com/BurntSushi/toml
+	switch t.Location().String() {
+	case "date-local":
+		return t.Format("2006-01-02")
+	case "time-local":
+		return t.Format("15:04:05.999999999")
+	case "datetime-local":
+		return t.Format("2006-01-02T15:04:05.999999999")
+	}
+	return t.Format(time.RFC3339Nano)
+}
@@ -0,0 +1,178 @@
+package properties_test
+
+import (
+	"testing"
+
+	"github.com/gookit/goutil/testutil/assert"
+	"github.com/gookit/properties"
+)
+
+var convertText = `
+name = inhere
+port = 8080
+debug = true
+desc = "some \"desc\" # not comment"
+db.url = jdbc:mysql://localhost/db
+db.pool.max-idle = 8
+tags[0] = a
+tags[1] = b c
+users[0].name = tom
+users[0].age = 23
+users[1].name = "jerry: mouse"
+`
+
+func mustParse(t *testing.T, text string) *properties.Parser {
+	p, err := properties.Parse(text)
+	assert.NoErr(t, err)
+	return p
+}
+
+// check the converted data is same as the source
+func assertSameData(t *testing.T, src, dst *properties.Parser) {
+	dr := properties.Diff(src, dst)
+	assert.True(t, dr.IsEmpty(), dr.String())
+}
+
+func TestJSON(t *testing.T) {
+	p := mustParse(t, convertText)
+
+	bs, err := properties.ToJSON(p, false)
+	assert.NoErr(t, err)
+	assert.StrContains(t, string(bs), `"max-idle": "8"`)
+
+	p2, err := properties.FromJSON(bs)
+	assert.NoErr(t, err)
+	assertSameData(t, p, p2)
+
+	// flat keys
+	bs, err = properties.ToJSON(p, true)
+	assert.NoErr(t, err)
+	assert.StrContains(t, string(bs), `"users[0].name": "tom"`)
+
+	p2, err = properties.FromJSON(bs)
+	assert.NoErr(t, err)
+	assertSameData(t, p, p2)
+
+	p2, err = properties.FromJSON([]byte(`{"port": 8080, "rate": 1.5, "on": true, "nil": null, "ls": [{"k": "v"}, {"k": "v1"}]}`))
+	assert.NoErr(t, err)
+	assert.Eq(t, "8080", p2.Str("port"))
+	assert.Eq(t, "1.5", p2.Str("rate"))
+	assert.Eq(t, "true", p2.Str("on"))
+	assert.Eq(t, "v", p2.SMap()["ls[0].k"])
+	// null value as empty string
+	assert.Eq(t, "", p2.SMap()["nil"])
+	assert.True(t, p2.Has("nil"))
+
+	_, err = properties.FromJSON([]byte(`[1, 2]`))
+	assert.Err(t, err)
+}
+
+func TestParser_TypedData(t *testing.T) {
+	p := mustParse(t, "flag = \"true\"\nnum = 8080\nrate = 1.5\nmode = 010\ndb.on = false\ntags[0] = \"1\"\ntags[1] = 2\n")
+	assert.Eq(t, map[string]any{
+		"flag": "true",
+		"num":  int64(8080),
+		"rate": 1.5,
+		"mode": "010",
+		"db":   map[string]any{"on": false},
+		"tags": []any{"1", int64(2)},
+	}, p.TypedData())
+
+	// Data is not changed
+	assert.Eq(t, "8080", p.Data["num"])
+}
+
+func TestINI(t *testing.T) {
+	p := mustParse(t, convertText)
+
+	bs, err := properties.ToINI(p)
+	assert.NoErr(t, err)
+	want := `debug = true
+desc = "some \"desc\" # not comment"
+name = inhere
+port = 8080
+tags[0] = a
+tags[1] = b c
+users[0].age = 23
+users[0].name = tom
+users[1].name = jerry: mouse
+
+[db]
+pool.max-idle = 8
+url = jdbc:mysql://localhost/db
+`
+	assert.Eq(t, want, string(bs))
+
+	p2, err := properties.FromINI(bs)
+	assert.NoErr(t, err)
+	assertSameData(t, p, p2)
+
+	p2, err = properties.FromINI([]byte(`
+; comments
+name = demo ; inline comments
+[db]
+host: localhost
+pwd = 'a;b'
+`))
+	assert.NoErr(t, err)
+	assert.Eq(t, "demo", p2.Str("name"))
+	assert.Eq(t, "localhost", p2.Str("db.host"))
+	assert.Eq(t, "a;b", p2.Str("db.pwd"))
+
+	_, err = properties.FromINI([]byte("[db"))
+	assert.Err(t, err)
+	_, err = properties.FromINI([]byte("invalid"))
+	assert.Err(t, err)
+}
+
+func TestDotenv(t *testing.T) {
+	p := mustParse(t, convertText)
+
+	bs, err := properties.ToDotenv(p, nil)
+	assert.NoErr(t, err)
+	want := `DB_POOL_MAX__IDLE=8
+DB_URL=jdbc:mysql://localhost/db
+DEBUG=true
+DESC="some \"desc\" # not comment"
+NAME=inhere
+PORT=8080
+TAGS_0=a
+TAGS_1="b c"
+USERS_0_AGE=23
+USERS_0_NAME=tom
+USERS_1_NAME="jerry: mouse"
+`
+	assert.Eq(t, want, string(bs))
+
+	p2, err := properties.FromDotenv(bs, nil)
+	assert.NoErr(t, err)
+	assertSameData(t, p, p2)
+
+	// with prefix and lower case
+	cfg := &properties.DotenvConfig{Prefix: "app_", LowerCase: true}
+	bs, err = properties.ToDotenv(p, cfg)
+	assert.NoErr(t, err)
+	assert.StrContains(t, string(bs), "app_db_url=jdbc:mysql://localhost/db\n")
+
+	p2, err = properties.FromDotenv(append(bs, "other=val\n"...), cfg)
+	assert.NoErr(t, err)
+	assertSameData(t, p, p2)
+
+	p2, err = properties.FromDotenv([]byte(`
+# comments
+export APP_NAME=demo # inline comments
+APP_DESC='single $quoted'
+APP_MULTI="line1
+line2"
+APP_EMPTY=
+`), &properties.DotenvConfig{Prefix: "APP_"})
+	assert.NoErr(t, err)
+	assert.Eq(t, "demo", p2.Str("name"))
+	assert.Eq(t, "single $quoted", p2.Str("desc"))
+	assert.Eq(t, "line1\nline2", p2.SMap()["multi"])
+
+	_, err = properties.FromDotenv([]byte("=val"), nil)
+	assert.Err(t, err)
+	_, err = properties.FromDotenv([]byte(`A="abc`), nil)
+	assert.Err(t, err)
+}
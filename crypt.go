@@ -0,0 +1,253 @@
+package properties
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/gookit/goutil/maputil"
+)
+
+// mark chars of the encrypted value. eg: ENC(base64...)
+const (
+	EncValuePrefix = "ENC("
+	EncValueSuffix = ")"
+)
+
+// Decryptor for decrypt the ENC(...) value. see Options.Decryptor
+type Decryptor interface {
+	Decrypt(data []byte) ([]byte, error)
+}
+
+// Encryptor for encrypt the value to ENC(...)
+type Encryptor interface {
+	Encrypt(plain []byte) ([]byte, error)
+}
+
+// AESGCM the builtin AES-GCM Encryptor and Decryptor.
+//
+// The encrypted data is: nonce + ciphertext(with tag).
+type AESGCM struct {
+	aead cipher.AEAD
+}
+
+// NewAESGCM instance. the key length must be 16, 24 or 32 bytes.
+func NewAESGCM(key []byte) (*AESGCM, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &AESGCM{aead: aead}, nil
+}
+
+// Encrypt the plain data, returns nonce + ciphertext
+func (c *AESGCM) Encrypt(plain []byte) ([]byte, error) {
+	nonce := make([]byte, c.aead.NonceSize(), c.aead.NonceSize()+len(plain)+c.aead.Overhead())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	return c.aead.Seal(nonce, nonce, plain, nil), nil
+}
+
+// Decrypt the data(nonce + ciphertext)
+func (c *AESGCM) Decrypt(data []byte) ([]byte, error) {
+	size := c.aead.NonceSize()
+	if len(data) < size+c.aead.Overhead() {
+		return nil, errors.New("invalid encrypted data, it is too short")
+	}
+	return c.aead.Open(nil, data[:size], data[size:], nil)
+}
+
+// settings of derive the AES key from passphrase by PBKDF2-HMAC-SHA256
+const (
+	kdfSaltSize   = 16
+	kdfIterations = 600000
+)
+
+// PassphraseAES the AES-GCM Encryptor and Decryptor with the 32 bytes key derived from a passphrase.
+//
+// The key is derived by PBKDF2-HMAC-SHA256 with a random salt, the encrypted data is:
+// salt + nonce + ciphertext(with tag). The salt is generated once for an instance,
+// and the derived keys are cached by the salt.
+type PassphraseAES struct {
+	passphrase []byte
+
+	mu   sync.Mutex
+	salt []byte
+	// cache the ciphers by salt, the key derivation is slow
+	ciphers map[string]*AESGCM
+}
+
+// NewPassphraseAES instance.
+func NewPassphraseAES(passphrase string) *PassphraseAES {
+	return &PassphraseAES{passphrase: []byte(passphrase), ciphers: make(map[string]*AESGCM)}
+}
+
+// Encrypt the plain data, returns salt + nonce + ciphertext
+func (c *PassphraseAES) Encrypt(plain []byte) ([]byte, error) {
+	c.mu.Lock()
+	if c.salt == nil {
+		salt := make([]byte, kdfSaltSize)
+		if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+			c.mu.Unlock()
+			return nil, err
+		}
+		c.salt = salt
+	}
+	salt := c.salt
+	c.mu.Unlock()
+
+	ac, err := c.cipherOf(salt)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := ac.Encrypt(plain)
+	if err != nil {
+		return nil, err
+	}
+	return append(salt[:len(salt):len(salt)], data...), nil
+}
+
+// Decrypt the data(salt + nonce + ciphertext)
+func (c *PassphraseAES) Decrypt(data []byte) ([]byte, error) {
+	if len(data) < kdfSaltSize {
+		return nil, errors.New("invalid encrypted data, it is too short")
+	}
+
+	ac, err := c.cipherOf(data[:kdfSaltSize])
+	if err != nil {
+		return nil, err
+	}
+	return ac.Decrypt(data[kdfSaltSize:])
+}
+
+func (c *PassphraseAES) cipherOf(salt []byte) (*AESGCM, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if ac, ok := c.ciphers[string(salt)]; ok {
+		return ac, nil
+	}
+
+	ac, err := NewAESGCM(pbkdf2Key(c.passphrase, salt, kdfIterations, 32))
+	if err != nil {
+		return nil, err
+	}
+	c.ciphers[string(salt)] = ac
+	return ac, nil
+}
+
+// pbkdf2Key derive key by PBKDF2 with HMAC-SHA256. see RFC 8018
+func pbkdf2Key(password, salt []byte, iter, keyLen int) []byte {
+	prf := hmac.New(sha256.New, password)
+	hashLen := prf.Size()
+	blocks := (keyLen + hashLen - 1) / hashLen
+
+	var idx [4]byte
+	dk := make([]byte, 0, blocks*hashLen)
+	u := make([]byte, hashLen)
+	for block := 1; block <= blocks; block++ {
+		prf.Reset()
+		prf.Write(salt)
+		binary.BigEndian.PutUint32(idx[:], uint32(block))
+		prf.Write(idx[:])
+		dk = prf.Sum(dk)
+
+		t := dk[len(dk)-hashLen:]
+		copy(u, t)
+		for n := 2; n <= iter; n++ {
+			prf.Reset()
+			prf.Write(u)
+			u = prf.Sum(u[:0])
+			for i := range u {
+				t[i] ^= u[i]
+			}
+		}
+	}
+	return dk[:keyLen]
+}
+
+// IsEncrypted check the value is an encrypted value. eg: ENC(base64...)
+func IsEncrypted(val string) bool {
+	return len(val) > len(EncValuePrefix)+len(EncValueSuffix) && strings.HasPrefix(val, EncValuePrefix) && strings.HasSuffix(val, EncValueSuffix)
+}
+
+// EncryptValue encrypt the plain value to ENC(base64...)
+func EncryptValue(enc Encryptor, plain string) (string, error) {
+	data, err := enc.Encrypt([]byte(plain))
+	if err != nil {
+		return "", err
+	}
+	return EncValuePrefix + base64.StdEncoding.EncodeToString(data) + EncValueSuffix, nil
+}
+
+// DecryptValue decrypt the ENC(base64...) value. will return the value on it's not encrypted.
+func DecryptValue(dec Decryptor, val string) (string, error) {
+	if !IsEncrypted(val) {
+		return val, nil
+	}
+
+	b64 := val[len(EncValuePrefix) : len(val)-len(EncValueSuffix)]
+	data, err := base64.StdEncoding.DecodeString(strings.TrimSpace(b64))
+	if err != nil {
+		return "", err
+	}
+
+	plain, err := dec.Decrypt(data)
+	if err != nil {
+		return "", err
+	}
+	return string(plain), nil
+}
+
+// EncryptKey encrypt the value of the key in the properties contents, will keep the layout and comments.
+//
+// Returns ErrNotFound on the key not exists, an error on the value already encrypted.
+func EncryptKey(src []byte, key string, enc Encryptor, optFns ...OpFunc) ([]byte, error) {
+	p, _, err := parseLines(src, optFns)
+	if err != nil {
+		return nil, err
+	}
+
+	e, ok := p.entries[key]
+	if !ok || e.Line == 0 {
+		return nil, ErrNotFound
+	}
+	if IsEncrypted(e.Value) {
+		return nil, fmt.Errorf("the value of key %q is already encrypted", key)
+	}
+
+	val, err := EncryptValue(enc, e.Value)
+	if err != nil {
+		return nil, err
+	}
+	return SetKeyValue(src, key, val, optFns...)
+}
+
+// MaskedSMap get the string map with the secret values masked, for logging and review.
+//
+// The secret keys are checked by IsSecretKey(key, words...), the decrypted values are also masked.
+func (p *Parser) MaskedSMap(words ...string) maputil.SMap {
+	smp := make(maputil.SMap, len(p.smap))
+	for key, val := range p.smap {
+		if e, ok := p.entries[key]; ok && e.Encrypted() || IsSecretKey(key, words...) {
+			val = MaskValue(val)
+		}
+		smp[key] = val
+	}
+	return smp
+}
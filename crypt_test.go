@@ -0,0 +1,185 @@
+package properties_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gookit/goutil/testutil/assert"
+	"github.com/gookit/properties"
+)
+
+// 32 bytes AES key for tests
+var testAESKey = []byte("0123456789abcdef0123456789abcdef")
+
+func TestEncryptValue(t *testing.T) {
+	_, err := properties.NewAESGCM([]byte("short"))
+	assert.Err(t, err)
+
+	c, err := properties.NewAESGCM(testAESKey)
+	assert.NoErr(t, err)
+
+	enc, err := properties.EncryptValue(c, "pwd234")
+	assert.NoErr(t, err)
+	assert.True(t, properties.IsEncrypted(enc))
+	assert.NotContains(t, enc, "pwd234")
+
+	plain, err := properties.DecryptValue(c, enc)
+	assert.NoErr(t, err)
+	assert.Eq(t, "pwd234", plain)
+
+	// not encrypted
+	plain, err = properties.DecryptValue(c, "abc")
+	assert.NoErr(t, err)
+	assert.Eq(t, "abc", plain)
+	assert.False(t, properties.IsEncrypted("ENC()"))
+
+	_, err = properties.DecryptValue(c, "ENC(invalid)")
+	assert.Err(t, err)
+	_, err = properties.DecryptValue(c, "ENC(YWJj)")
+	assert.ErrSubMsg(t, err, "too short")
+}
+
+func TestPassphraseAES(t *testing.T) {
+	c := properties.NewPassphraseAES("my-secret")
+	enc1, err := properties.EncryptValue(c, "pwd234")
+	assert.NoErr(t, err)
+	enc2, err := properties.EncryptValue(c, "pwd234")
+	assert.NoErr(t, err)
+	assert.NotEq(t, enc1, enc2)
+
+	// decrypt by a new instance, the salt is read from the encrypted data
+	c2 := properties.NewPassphraseAES("my-secret")
+	plain, err := properties.DecryptValue(c2, enc1)
+	assert.NoErr(t, err)
+	assert.Eq(t, "pwd234", plain)
+
+	// the salt is different for each instance
+	enc3, err := properties.EncryptValue(c2, "pwd234")
+	assert.NoErr(t, err)
+	plain, err = properties.DecryptValue(c, enc3)
+	assert.NoErr(t, err)
+	assert.Eq(t, "pwd234", plain)
+
+	_, err = properties.DecryptValue(properties.NewPassphraseAES("other"), enc1)
+	assert.Err(t, err)
+	_, err = properties.DecryptValue(c, "ENC(YWJj)")
+	assert.ErrSubMsg(t, err, "too short")
+}
+
+func TestParser_Parse_decrypt(t *testing.T) {
+	c, err := properties.NewAESGCM(testAESKey)
+	assert.NoErr(t, err)
+
+	enc, err := properties.EncryptValue(c, "pwd234")
+	assert.NoErr(t, err)
+	text := "spring.redis.host = localhost\nspring.redis.password = " + enc + "\n"
+
+	p := properties.NewParser(properties.WithDecryptor(c))
+	assert.NoErr(t, p.Parse(text))
+	assert.Eq(t, "pwd234", p.Str("spring.redis.password"))
+
+	e, ok := p.Entry("spring.redis.password")
+	assert.True(t, ok)
+	assert.True(t, e.Encrypted())
+
+	// encode will keep the encrypted value
+	bs, err := properties.Encode(p)
+	assert.NoErr(t, err)
+	assert.StrContains(t, string(bs), "spring.redis.password="+enc)
+
+	smp := p.MaskedSMap()
+	assert.Eq(t, properties.MaskedValue, smp["spring.redis.password"])
+	assert.Eq(t, "localhost", smp["spring.redis.host"])
+
+	// without decryptor
+	p = properties.NewParser()
+	assert.NoErr(t, p.Parse(text))
+	assert.Eq(t, enc, p.Str("spring.redis.password"))
+
+	// wrong key
+	c2, err := properties.NewAESGCM([]byte("fedcba9876543210fedcba9876543210"))
+	assert.NoErr(t, err)
+	p = properties.NewParser(properties.WithDecryptor(c2))
+	assert.ErrSubMsg(t, p.Parse(text), `decrypt the value of key "spring.redis.password"`)
+}
+
+func TestEncoder_MaskSecrets(t *testing.T) {
+	enc := properties.NewEncoder()
+	enc.MaskSecrets = true
+
+	bs, err := enc.Encode(map[string]any{
+		"spring": map[string]any{
+			"redis": map[string]any{
+				"host":     "localhost",
+				"password": "pwd234",
+			},
+		},
+		"api_token": "abc",
+	})
+	assert.NoErr(t, err)
+	assert.Eq(t, "api_token=******\nspring.redis.host=localhost\nspring.redis.password=******\n", string(bs))
+
+	enc = properties.NewEncoder()
+	enc.MaskSecrets = true
+	enc.SecretKeys = []string{"host"}
+	bs, err = enc.Encode(map[string]string{"host": "localhost", "password": "pwd234"})
+	assert.NoErr(t, err)
+	assert.Eq(t, "host=******\npassword=pwd234\n", string(bs))
+}
+
+func TestEncryptKey(t *testing.T) {
+	c, err := properties.NewAESGCM(testAESKey)
+	assert.NoErr(t, err)
+
+	src := []byte("# redis\nspring.redis.password = pwd234\nname = inhere\n")
+	out, err := properties.EncryptKey(src, "spring.redis.password", c)
+	assert.NoErr(t, err)
+	assert.True(t, strings.HasPrefix(string(out), "# redis\nspring.redis.password = ENC("))
+	assert.StrContains(t, string(out), ")\nname = inhere\n")
+
+	p := properties.NewParser(properties.WithDecryptor(c))
+	assert.NoErr(t, p.ParseBytes(out))
+	assert.Eq(t, "pwd234", p.Str("spring.redis.password"))
+
+	_, err = properties.EncryptKey(out, "spring.redis.password", c)
+	assert.ErrSubMsg(t, err, "already encrypted")
+	_, err = properties.EncryptKey(src, "not-exist", c)
+	assert.ErrIs(t, err, properties.ErrNotFound)
+}
+
+func TestEncryptedValue_mask(t *testing.T) {
+	c, err := properties.NewAESGCM(testAESKey)
+	assert.NoErr(t, err)
+
+	enc, err := properties.EncryptValue(c, "hunter2")
+	assert.NoErr(t, err)
+	text := "db.conn = " + enc + "\nname = app\n"
+
+	p := properties.NewParser(properties.WithDecryptor(c))
+	assert.NoErr(t, p.Parse(text))
+
+	// mask the decrypted value, even the key is not a secret key
+	e := properties.NewEncoder()
+	e.MaskSecrets = true
+	bs, err := e.Encode(p)
+	assert.NoErr(t, err)
+	assert.Eq(t, "db.conn=******\nname=app\n", string(bs))
+
+	// overridden by env, will not keep the ENC value
+	assert.NoErr(t, p.OverlayEnvMap("APP_", map[string]string{"APP_DB_CONN": "other"}))
+	bs, err = properties.Encode(p)
+	assert.NoErr(t, err)
+	assert.Eq(t, "db.conn=other\nname=app\n", string(bs))
+
+	// layered dump
+	l := properties.NewLayered(properties.WithDecryptor(c))
+	assert.NoErr(t, l.LoadText("a.properties", text))
+	assert.NoErr(t, l.LoadText("b.properties", "db.conn = plain\n"))
+	out := l.DumpString()
+	assert.StrContains(t, out, `db.conn = plain # from: b.properties:1, overrides: a.properties:1`+"\n")
+	assert.NotContains(t, out, "hunter2")
+
+	l = properties.NewLayered(properties.WithDecryptor(c))
+	assert.NoErr(t, l.LoadText("a.properties", text))
+	assert.StrContains(t, l.DumpString(), "db.conn = ****** # from: a.properties:1\n")
+}
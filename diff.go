@@ -0,0 +1,138 @@
+package properties
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ChangeType of the key value
+type ChangeType uint8
+
+// change types
+const (
+	ChangeAdded ChangeType = iota + 1
+	ChangeUpdated
+	ChangeRemoved
+)
+
+// String name of the change type
+func (ct ChangeType) String() string {
+	switch ct {
+	case ChangeAdded:
+		return "added"
+	case ChangeUpdated:
+		return "changed"
+	case ChangeRemoved:
+		return "removed"
+	}
+	return "unknown"
+}
+
+// Change of a key between two parsed documents
+type Change struct {
+	Key  string
+	Type ChangeType
+	// OldValue is empty on added
+	OldValue string
+	// NewValue is empty on removed
+	NewValue string
+	// OldLine, NewLine number of the key in the source, is 0 on not exists.
+	OldLine, NewLine int
+}
+
+// diff the raw string values of the keys, returns changes sorted by key.
+func diffParsers(a, b *Parser) []Change {
+	var changes []Change
+	for key, oldVal := range a.smap {
+		newVal, ok := b.smap[key]
+		if !ok {
+			changes = append(changes, Change{
+				Key:      key,
+				Type:     ChangeRemoved,
+				OldValue: oldVal,
+				OldLine:  a.lineOf(key),
+			})
+		} else if newVal != oldVal {
+			changes = append(changes, Change{
+				Key:      key,
+				Type:     ChangeUpdated,
+				OldValue: oldVal,
+				NewValue: newVal,
+				OldLine:  a.lineOf(key),
+				NewLine:  b.lineOf(key),
+			})
+		}
+	}
+
+	for key, newVal := range b.smap {
+		if _, ok := a.smap[key]; !ok {
+			changes = append(changes, Change{
+				Key:      key,
+				Type:     ChangeAdded,
+				NewValue: newVal,
+				NewLine:  b.lineOf(key),
+			})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Key < changes[j].Key
+	})
+	return changes
+}
+
+// line number of the key in the source
+func (p *Parser) lineOf(key string) int {
+	if e, ok := p.entries[key]; ok {
+		return e.Line
+	}
+	return 0
+}
+
+// DiffResult of two parsed documents, each list is sorted by key.
+type DiffResult struct {
+	Added   []Change
+	Removed []Change
+	Changed []Change
+}
+
+// Diff compare the keys and values between a and b.
+//
+// The values are compared by the raw string value. eg: Parser.SMap()
+func Diff(a, b *Parser) *DiffResult {
+	dr := &DiffResult{}
+	for _, c := range diffParsers(a, b) {
+		switch c.Type {
+		case ChangeAdded:
+			dr.Added = append(dr.Added, c)
+		case ChangeRemoved:
+			dr.Removed = append(dr.Removed, c)
+		default:
+			dr.Changed = append(dr.Changed, c)
+		}
+	}
+	return dr
+}
+
+// IsEmpty check there are no changes
+func (dr *DiffResult) IsEmpty() bool {
+	return len(dr.Added)+len(dr.Removed)+len(dr.Changed) == 0
+}
+
+// String format the diff result for review.
+//
+// Line prefix: "+" is added, "-" is removed, "~" is changed. eg: "~ db.url = dev -> prod (line 2 -> 2)"
+func (dr *DiffResult) String() string {
+	var sb strings.Builder
+	for _, c := range dr.Added {
+		sb.WriteString(fmt.Sprintf("+ %s = %s (line %d)\n", c.Key, c.NewValue, c.NewLine))
+	}
+	for _, c := range dr.Removed {
+		sb.WriteString(fmt.Sprintf("- %s = %s (line %d)\n", c.Key, c.OldValue, c.OldLine))
+	}
+	for _, c := range dr.Changed {
+		sb.WriteString(fmt.Sprintf("~ %s = %s -> %s (line %d -> %d)\n", c.Key, c.OldValue, c.NewValue, c.OldLine, c.NewLine))
+	}
+	return sb.String()
+}
@@ -0,0 +1,158 @@
+package properties_test
+
+import (
+	"testing"
+
+	"github.com/gookit/goutil/testutil/assert"
+	"github.com/gookit/properties"
+)
+
+func TestDiff(t *testing.T) {
+	a, err := properties.Parse(`
+name = demo
+db.url = jdbc:dev
+db.pool = 5
+`)
+	assert.NoErr(t, err)
+
+	b, err := properties.Parse(`
+name = demo
+db.url = jdbc:prod
+db.host = localhost
+`)
+	assert.NoErr(t, err)
+
+	dr := properties.Diff(a, b)
+	assert.False(t, dr.IsEmpty())
+	assert.Len(t, dr.Added, 1)
+	assert.Eq(t, "db.host", dr.Added[0].Key)
+	assert.Eq(t, 4, dr.Added[0].NewLine)
+	assert.Len(t, dr.Removed, 1)
+	assert.Eq(t, "db.pool", dr.Removed[0].Key)
+	assert.Eq(t, "5", dr.Removed[0].OldValue)
+	assert.Len(t, dr.Changed, 1)
+	assert.Eq(t, properties.ChangeUpdated, dr.Changed[0].Type)
+	assert.Eq(t, "jdbc:dev", dr.Changed[0].OldValue)
+	assert.Eq(t, "jdbc:prod", dr.Changed[0].NewValue)
+
+	want := `+ db.host = localhost (line 4)
+- db.pool = 5 (line 4)
+~ db.url = jdbc:dev -> jdbc:prod (line 3 -> 3)
+`
+	assert.Eq(t, want, dr.String())
+	assert.True(t, properties.Diff(a, a).IsEmpty())
+}
+
+func TestMerge(t *testing.T) {
+	newDst := func() *properties.Parser {
+		p, err := properties.Parse(`
+name = demo
+# db url
+db.url = jdbc:dev
+db.pool = 5
+tags[0] = a
+tags[1] = b
+tags[2] = c
+`)
+		assert.NoErr(t, err)
+		return p
+	}
+
+	src, err := properties.Parse(`
+# prod db url
+db.url = jdbc:prod
+db.host = localhost
+tags[0] = x
+tags[1] = y
+`)
+	assert.NoErr(t, err)
+
+	t.Run("override", func(t *testing.T) {
+		dst := newDst()
+		assert.NoErr(t, properties.Merge(dst, src, properties.MergeOverride))
+		assert.Eq(t, "demo", dst.Str("name"))
+		assert.Eq(t, "jdbc:prod", dst.Str("db.url"))
+		assert.Eq(t, "localhost", dst.Str("db.host"))
+		assert.Eq(t, "5", dst.Str("db.pool"))
+		assert.Eq(t, []string{"x", "y"}, dst.Strings("tags"))
+		assert.Eq(t, "# prod db url", dst.Comments()["db.url"])
+
+		smp := dst.SMap()
+		assert.Eq(t, "jdbc:prod", smp["db.url"])
+		assert.Eq(t, "x", smp["tags[0]"])
+		assert.NotContains(t, smp.Keys(), "tags[2]")
+		assert.False(t, properties.Diff(src, dst).IsEmpty())
+
+		// src not be changed on modify dst
+		dst.Data["db"].(map[string]any)["host"] = "changed"
+		assert.Eq(t, "localhost", src.Str("db.host"))
+	})
+
+	t.Run("keep existing", func(t *testing.T) {
+		dst := newDst()
+		assert.NoErr(t, properties.Merge(dst, src, properties.MergeKeepExisting))
+		assert.Eq(t, "jdbc:dev", dst.Str("db.url"))
+		assert.Eq(t, "localhost", dst.Str("db.host"))
+		assert.Eq(t, []string{"a", "b", "c"}, dst.Strings("tags"))
+		assert.Eq(t, "# db url", dst.Comments()["db.url"])
+	})
+
+	t.Run("error on conflict", func(t *testing.T) {
+		dst := newDst()
+		err := properties.Merge(dst, src, properties.MergeErrorOnConflict)
+		assert.ErrIs(t, err, properties.ErrMergeConflict)
+		assert.ErrMsg(t, err, `merge conflict on key "db.url"`)
+		// not modified
+		assert.Eq(t, "jdbc:dev", dst.Str("db.url"))
+		assert.Eq(t, "", dst.Str("db.host"))
+
+		other, err := properties.Parse("name = demo\nport = 80")
+		assert.NoErr(t, err)
+		dst = newDst()
+		assert.NoErr(t, properties.Merge(dst, other, properties.MergeErrorOnConflict))
+		assert.Eq(t, "80", dst.Str("port"))
+	})
+
+	t.Run("inline slice and map", func(t *testing.T) {
+		dst, err := properties.Parse("tags = [a, b, c]\nmp = {k1: v1}", properties.ParseInlineSlice)
+		assert.NoErr(t, err)
+		src, err := properties.Parse("tags[0] = x\nmp.k2 = v2", properties.ParseInlineSlice)
+		assert.NoErr(t, err)
+
+		assert.NoErr(t, properties.Merge(dst, src, properties.MergeOverride))
+		assert.Eq(t, []string{"x"}, dst.Strings("tags"))
+		assert.Eq(t, map[string]any{"k1": "v1", "k2": "v2"}, dst.Get("mp"))
+	})
+
+	t.Run("scalar and nested keys", func(t *testing.T) {
+		// scalar override the nested keys
+		dst := mustParse(t, "top.sub = y\ntop.sub2 = z\nname = demo")
+		assert.NoErr(t, properties.Merge(dst, mustParse(t, "top = x"), properties.MergeOverride))
+		assert.Eq(t, "x", dst.Str("top"))
+		assert.Eq(t, map[string]string{"top": "x", "name": "demo"}, map[string]string(dst.SMap()))
+
+		// nested keys override the scalar
+		dst = mustParse(t, "top = x\nname = demo")
+		assert.NoErr(t, properties.Merge(dst, mustParse(t, "top.sub = y"), properties.MergeOverride))
+		assert.Eq(t, "y", dst.Str("top.sub"))
+		assert.Eq(t, map[string]string{"top.sub": "y", "name": "demo"}, map[string]string(dst.SMap()))
+
+		dst = mustParse(t, "top = x")
+		assert.NoErr(t, properties.Merge(dst, mustParse(t, "top.sub = y"), properties.MergeKeepExisting))
+		assert.Eq(t, "x", dst.Str("top"))
+		assert.Eq(t, map[string]string{"top": "x"}, map[string]string(dst.SMap()))
+
+		err := properties.Merge(dst, mustParse(t, "top.sub = y\nother = v"), properties.MergeErrorOnConflict)
+		assert.ErrMsg(t, err, `merge conflict on key "top.sub"`)
+		assert.False(t, dst.Has("other"))
+	})
+
+	t.Run("not modify dst on error", func(t *testing.T) {
+		dst := mustParse(t, "tags[0] = a\nname = demo")
+		src := mustParse(t, "new = v\ntags.k = b")
+		assert.Err(t, properties.Merge(dst, src, properties.MergeKeepExisting))
+		assert.Eq(t, map[string]string{"tags[0]": "a", "name": "demo"}, map[string]string(dst.SMap()))
+		assert.False(t, dst.Has("new"))
+		assert.Len(t, dst.Data, 2)
+	})
+}
@@ -0,0 +1,166 @@
+package properties
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// DotenvConfig for convert between properties and dotenv
+type DotenvConfig struct {
+	// Prefix for the env var name. eg: "APP_"
+	//
+	// On FromDotenv, only the env vars with the prefix will be collected.
+	Prefix string
+	// LowerCase env var name on ToDotenv. default: upper case
+	LowerCase bool
+	// KeyMapper convert the env var name to key on FromDotenv. default: DefaultEnvKeyMapper
+	KeyMapper EnvKeyMapper
+	// NameMapper convert the key to env var name on ToDotenv. default: EnvName
+	NameMapper func(key string) string
+}
+
+// EnvName convert the key to env var name, is reverse of the DefaultEnvKeyMapper. eg:
+//
+//	server.port -> SERVER_PORT
+//	server.max-wait -> SERVER_MAX__WAIT
+//	users[0].name -> USERS_0_NAME
+func EnvName(key string) string {
+	name := strings.NewReplacer(".", "_", "-", "__", "[", "_", "]", "").Replace(key)
+	return strings.ToUpper(name)
+}
+
+func (c *DotenvConfig) envName(key string) string {
+	var name string
+	if c.NameMapper != nil {
+		name = c.NameMapper(key)
+	} else {
+		name = EnvName(key)
+	}
+
+	if c.LowerCase {
+		name = strings.ToLower(name)
+	}
+	return c.Prefix + name
+}
+
+// ToDotenv convert the parsed data to dotenv contents. cfg is optional.
+//
+// eg: "db.url=jdbc" -> "DB_URL=jdbc"
+func ToDotenv(p *Parser, cfg *DotenvConfig) ([]byte, error) {
+	if cfg == nil {
+		cfg = &DotenvConfig{}
+	}
+
+	keys := p.smap.Keys()
+	sort.Strings(keys)
+
+	var buf bytes.Buffer
+	for _, key := range keys {
+		val := p.smap[key]
+		if val != strings.TrimSpace(val) || strings.ContainsAny(val, " #\"'$\\\n\r\t") {
+			val = quoteValue(val, QuoteDouble)
+		}
+		buf.WriteString(cfg.envName(key) + "=" + val + "\n")
+	}
+	return buf.Bytes(), nil
+}
+
+// FromDotenv convert dotenv contents to properties Parser. cfg is optional.
+//
+// eg: "DB_URL=jdbc" -> "db.url=jdbc"
+func FromDotenv(bs []byte, cfg *DotenvConfig, optFns ...OpFunc) (*Parser, error) {
+	if cfg == nil {
+		cfg = &DotenvConfig{}
+	}
+
+	mapper := cfg.KeyMapper
+	if mapper == nil {
+		mapper = DefaultEnvKeyMapper
+	}
+
+	envs, err := parseDotenv(bs)
+	if err != nil {
+		return nil, err
+	}
+
+	data := make(map[string]any, len(envs))
+	for name, val := range envs {
+		if !strings.HasPrefix(name, cfg.Prefix) {
+			continue
+		}
+
+		if key := mapper(strings.TrimPrefix(name, cfg.Prefix)); key != "" {
+			data[key] = val
+		}
+	}
+	return FromMap(data, optFns...)
+}
+
+// parse dotenv contents to map. support "export NAME=val", quoted and multi line quoted value.
+func parseDotenv(bs []byte) (map[string]string, error) {
+	envs := make(map[string]string)
+
+	var lineNo int
+	s := bufio.NewScanner(bytes.NewReader(bs))
+	for s.Scan() {
+		lineNo++
+		line := strings.TrimSpace(s.Text())
+		if line == "" || line[0] == '#' {
+			continue
+		}
+
+		line = strings.TrimPrefix(line, "export ")
+		name, val, ok := strings.Cut(line, "=")
+		if name = strings.TrimSpace(name); !ok || name == "" {
+			return nil, fmt.Errorf("dotenv: line %d: invalid line %q", lineNo, line)
+		}
+
+		val = strings.TrimSpace(val)
+		if val == "" {
+			envs[name] = ""
+			continue
+		}
+
+		switch quote := val[0]; quote {
+		case '"', '\'':
+			// multi line quoted value
+			for !isQuoteClosed(val, quote) && s.Scan() {
+				lineNo++
+				val += "\n" + s.Text()
+			}
+
+			end := strings.LastIndexByte(val, quote)
+			if end < 1 {
+				return nil, fmt.Errorf("dotenv: line %d: quoted value not closed", lineNo)
+			}
+
+			val = val[1:end]
+			if quote == '"' {
+				val = unescapeValue(val)
+			}
+		default:
+			if pos := strings.Index(val, " #"); pos > -1 {
+				val = strings.TrimSpace(val[:pos])
+			}
+		}
+		envs[name] = val
+	}
+	return envs, s.Err()
+}
+
+// check the quoted value is closed. eg: "abc"
+func isQuoteClosed(s string, quote byte) bool {
+	for i := 1; i < len(s); i++ {
+		if s[i] == '\\' && quote == '"' {
+			i++
+			continue
+		}
+		if s[i] == quote {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,139 @@
+package properties
+
+import (
+	"regexp"
+	"strings"
+)
+
+// SetKeyValue set the key value in the properties contents, will keep the layout and comments.
+//
+// If the key exists, only the value will be replaced. otherwise will append it to the end.
+func SetKeyValue(src []byte, key, value string, optFns ...OpFunc) ([]byte, error) {
+	p, lines, err := parseLines(src, optFns)
+	if err != nil {
+		return nil, err
+	}
+
+	e, ok := p.entries[key]
+	if !ok || e.Line == 0 {
+		sep := "="
+		if detectSpacedSep(p, lines) {
+			sep = " = "
+		}
+
+		// keep the last empty line
+		last := len(lines) - 1
+		if last >= 0 && lines[last] == "" {
+			lines = append(lines[:last], key+sep+formatValue(value, QuoteNone), "")
+		} else {
+			lines = append(lines, key+sep+formatValue(value, QuoteNone))
+		}
+		return []byte(strings.Join(lines, "\n")), nil
+	}
+
+	// keep the key and separator style. eg: "  key = "
+	prefix := keyPrefixRegex(key).FindString(lines[e.Line-1])
+	if prefix == "" {
+		prefix = key + "="
+	}
+
+	newLine := prefix + formatValue(value, e.Quote)
+	lines = spliceLines(lines, e.Line-1, e.lastLine(), newLine)
+	return []byte(strings.Join(lines, "\n")), nil
+}
+
+// DeleteKey delete the key in the properties contents, the comments above the key will be deleted too.
+//
+// Returns ErrNotFound on the key not exists.
+func DeleteKey(src []byte, key string, optFns ...OpFunc) ([]byte, error) {
+	p, lines, err := parseLines(src, optFns)
+	if err != nil {
+		return nil, err
+	}
+
+	e, ok := p.entries[key]
+	if !ok || e.Line == 0 {
+		return nil, ErrNotFound
+	}
+
+	// comments lines above the key
+	start := e.Line - 1
+	for start > 0 && isCommentLine(lines[start-1]) {
+		start--
+	}
+
+	lines = spliceLines(lines, start, e.lastLine())
+	return []byte(strings.Join(lines, "\n")), nil
+}
+
+func parseLines(src []byte, optFns []OpFunc) (*Parser, []string, error) {
+	p := NewParser(optFns...)
+	// keep the raw value, not overlay env and decrypt
+	p.opts.EnvPrefix = ""
+	p.opts.Decryptor = nil
+
+	text := strings.ReplaceAll(string(src), "\r\n", "\n")
+	if strings.TrimSpace(text) != "" {
+		if err := p.Parse(text); err != nil {
+			return nil, nil, err
+		}
+	}
+	return p, strings.Split(text, "\n"), nil
+}
+
+// replace the lines[start:end] by the newLines.
+func spliceLines(lines []string, start, end int, newLines ...string) []string {
+	if end > len(lines) {
+		end = len(lines)
+	}
+
+	ls := make([]string, 0, len(lines)-(end-start)+len(newLines))
+	ls = append(ls, lines[:start]...)
+	ls = append(ls, newLines...)
+	return append(ls, lines[end:]...)
+}
+
+// lastLine number of the entry value
+func (e *Entry) lastLine() int {
+	if e.endLine > e.Line {
+		return e.endLine
+	}
+	return e.Line
+}
+
+func keyPrefixRegex(key string) *regexp.Regexp {
+	return regexp.MustCompile(`^\s*` + regexp.QuoteMeta(key) + `\s*[=:]\s*`)
+}
+
+// detect the key-value separator style is "key = value"
+func detectSpacedSep(p *Parser, lines []string) bool {
+	var spaced, compact int
+	for _, e := range p.entries {
+		if e.Line == 0 {
+			continue
+		}
+
+		if loc := keyPrefixRegex(e.Key).FindString(lines[e.Line-1]); strings.HasSuffix(loc, " ") {
+			spaced++
+		} else {
+			compact++
+		}
+	}
+	return spaced > compact
+}
+
+func isCommentLine(line string) bool {
+	line = strings.TrimSpace(line)
+	return line != "" && (line[0] == '#' || line[0] == '!' || strings.HasPrefix(line, "//"))
+}
+
+// format the value for write to properties contents.
+func formatValue(val string, quote byte) string {
+	if quote != QuoteNone || needQuote(val) {
+		if quote == QuoteNone {
+			quote = QuoteDouble
+		}
+		return quoteValue(val, quote)
+	}
+	return val
+}
@@ -0,0 +1,80 @@
+package properties_test
+
+import (
+	"testing"
+
+	"github.com/gookit/goutil/testutil/assert"
+	"github.com/gookit/properties"
+)
+
+func TestSetKeyValue(t *testing.T) {
+	src := `# app name
+name = demo
+
+# multi line value
+desc = line1 \
+  line2
+port=8080
+quoted = "a b"
+`
+	bs, err := properties.SetKeyValue([]byte(src), "desc", "new desc")
+	assert.NoErr(t, err)
+	assert.Eq(t, `# app name
+name = demo
+
+# multi line value
+desc = new desc
+port=8080
+quoted = "a b"
+`, string(bs))
+
+	bs, err = properties.SetKeyValue(bs, "quoted", "c d")
+	assert.NoErr(t, err)
+	assert.StrContains(t, string(bs), "quoted = \"c d\"\n")
+
+	bs, err = properties.SetKeyValue(bs, "port", " 9090")
+	assert.NoErr(t, err)
+	assert.StrContains(t, string(bs), "port=\" 9090\"\n")
+
+	// append new key
+	bs, err = properties.SetKeyValue(bs, "db.url", "jdbc:mysql://localhost")
+	assert.NoErr(t, err)
+	assert.StrContains(t, string(bs), "quoted = \"c d\"\ndb.url = jdbc:mysql://localhost\n")
+
+	bs, err = properties.SetKeyValue(nil, "name", "multi\nline")
+	assert.NoErr(t, err)
+	assert.Eq(t, "name=\"multi\\nline\"\n", string(bs))
+
+	p, err := properties.Parse(string(bs))
+	assert.NoErr(t, err)
+	assert.Eq(t, "multi\nline", p.Str("name"))
+
+	_, err = properties.SetKeyValue([]byte("key = '''abc"), "key", "val")
+	assert.Err(t, err)
+}
+
+func TestDeleteKey(t *testing.T) {
+	src := `# app name
+name = demo
+
+# multi line value
+# line 2
+desc = line1 \
+  line2
+port=8080
+`
+	bs, err := properties.DeleteKey([]byte(src), "desc")
+	assert.NoErr(t, err)
+	assert.Eq(t, `# app name
+name = demo
+
+port=8080
+`, string(bs))
+
+	bs, err = properties.DeleteKey(bs, "name")
+	assert.NoErr(t, err)
+	assert.Eq(t, "\nport=8080\n", string(bs))
+
+	_, err = properties.DeleteKey(bs, "not-exists")
+	assert.ErrIs(t, err, properties.ErrNotFound)
+}
@@ -0,0 +1,140 @@
+package properties
+
+import (
+	"encoding"
+	"net"
+	"net/url"
+	"reflect"
+	"time"
+)
+
+// EncodeHookFunc convert value to string on encode.
+//
+// Returns ok=false on the value is not handled by the hook.
+type EncodeHookFunc func(rv reflect.Value) (str string, ok bool, err error)
+
+// DefaultTimeLayout for encode time.Time value
+var DefaultTimeLayout = time.RFC3339
+
+var (
+	urlPtrType       = reflect.PointerTo(urlType)
+	ipNetPtrType     = reflect.PointerTo(ipNetType)
+	regexpPtrType    = reflect.PointerTo(regexpType)
+	textMarshalerTyp = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// EncodeHooks returns all builtin encode hooks, the order is:
+//
+//	duration, time, url, ip, regexp, text marshaler
+func EncodeHooks(timeLayout string) []EncodeHookFunc {
+	return []EncodeHookFunc{
+		DurationEncodeHook(),
+		TimeEncodeHook(timeLayout),
+		URLEncodeHook(),
+		IPEncodeHook(),
+		RegexpEncodeHook(),
+		TextMarshalerEncodeHook(),
+	}
+}
+
+// DurationEncodeHook encode time.Duration to string. eg: 3s, 1h30m0s
+func DurationEncodeHook() EncodeHookFunc {
+	return func(rv reflect.Value) (string, bool, error) {
+		if rv.Type() != durationType {
+			return "", false, nil
+		}
+		return time.Duration(rv.Int()).String(), true, nil
+	}
+}
+
+// TimeEncodeHook encode time.Time to string by layout. default use DefaultTimeLayout
+func TimeEncodeHook(layout string) EncodeHookFunc {
+	return func(rv reflect.Value) (string, bool, error) {
+		if rv.Type() != timeType {
+			return "", false, nil
+		}
+
+		useLayout := layout
+		if useLayout == "" {
+			useLayout = DefaultTimeLayout
+		}
+		return rv.Interface().(time.Time).Format(useLayout), true, nil
+	}
+}
+
+// URLEncodeHook encode url.URL, *url.URL to string
+func URLEncodeHook() EncodeHookFunc {
+	return func(rv reflect.Value) (string, bool, error) {
+		switch rv.Type() {
+		case urlType:
+			u := rv.Interface().(url.URL)
+			return u.String(), true, nil
+		case urlPtrType:
+			return rv.Interface().(*url.URL).String(), true, nil
+		}
+		return "", false, nil
+	}
+}
+
+// IPEncodeHook encode net.IP, net.IPNet, *net.IPNet to string. nil or zero value is encoded as empty string.
+func IPEncodeHook() EncodeHookFunc {
+	return func(rv reflect.Value) (string, bool, error) {
+		switch rv.Type() {
+		case ipType:
+			return ipString(rv.Interface().(net.IP)), true, nil
+		case ipNetType:
+			ipNet := rv.Interface().(net.IPNet)
+			return ipNetString(&ipNet), true, nil
+		case ipNetPtrType:
+			return ipNetString(rv.Interface().(*net.IPNet)), true, nil
+		}
+		return "", false, nil
+	}
+}
+
+func ipString(ip net.IP) string {
+	if len(ip) == 0 {
+		return ""
+	}
+	return ip.String()
+}
+
+func ipNetString(ipNet *net.IPNet) string {
+	if ipNet == nil || len(ipNet.IP) == 0 {
+		return ""
+	}
+	return ipNet.String()
+}
+
+// RegexpEncodeHook encode *regexp.Regexp to pattern string
+func RegexpEncodeHook() EncodeHookFunc {
+	return func(rv reflect.Value) (string, bool, error) {
+		if rv.Type() != regexpPtrType {
+			return "", false, nil
+		}
+		return rv.Interface().(interface{ String() string }).String(), true, nil
+	}
+}
+
+// TextMarshalerEncodeHook encode value by encoding.TextMarshaler
+func TextMarshalerEncodeHook() EncodeHookFunc {
+	return func(rv reflect.Value) (string, bool, error) {
+		if rv.Kind() == reflect.Interface {
+			return "", false, nil
+		}
+
+		// method on pointer receiver
+		if !rv.Type().Implements(textMarshalerTyp) {
+			if rv.Kind() == reflect.Ptr || !reflect.PointerTo(rv.Type()).Implements(textMarshalerTyp) {
+				return "", false, nil
+			}
+
+			ptr := reflect.New(rv.Type())
+			ptr.Elem().Set(rv)
+			rv = ptr
+		}
+
+		bs, err := rv.Interface().(encoding.TextMarshaler).MarshalText()
+		return string(bs), err == nil, err
+	}
+}
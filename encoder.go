@@ -4,20 +4,85 @@ import (
 	"bytes"
 	"errors"
 	"reflect"
+	"sort"
+	"strconv"
 	"strings"
 
-	"github.com/go-viper/mapstructure/v2"
 	"github.com/gookit/goutil/reflects"
 )
 
+// SliceStyle for encode slice value
+type SliceStyle uint8
+
+// slice styles for encode slice value
+const (
+	// SliceIndexed encode slice as indexed keys. eg: key[0]=a
+	SliceIndexed SliceStyle = iota
+	// SliceInline encode slice as inline value. eg: key=[a, b]
+	SliceInline
+	// SliceComma encode slice as comma separated value, like Spring. eg: key=a,b
+	//
+	// NOTE: will fallback to SliceIndexed if element is not scalar or contains comma.
+	SliceComma
+)
+
+// NilStyle for encode nil value
+type NilStyle uint8
+
+// nil styles for encode nil pointer, interface value
+const (
+	// NilSkip skip the nil value
+	NilSkip NilStyle = iota
+	// NilEmpty write nil value as empty. eg: key=
+	NilEmpty
+	// NilKeyOnly write key only for nil value. eg: key
+	//
+	// NOTE: parse the output should enable Options.BareKey, see ParseBareKey
+	NilKeyOnly
+)
+
 // Encoder struct
 type Encoder struct {
 	buf bytes.Buffer
 	// TagName for encode a struct. default: properties
 	TagName string
+	// SliceStyle for encode slice value. default: SliceIndexed
+	SliceStyle SliceStyle
+	// OmitEmpty skip zero values, nil and empty map, slice on encode. default: false
+	//
+	// Can also use tag option for a field. eg: `properties:"name,omitempty"`
+	OmitEmpty bool
+	// NilStyle for encode nil pointer, interface value. default: NilSkip
+	NilStyle NilStyle
+	// NameStrategy for convert key name of untagged fields. default: use field name
+	//
+	// eg: KebabCase, SnakeCase, LowerCamel, DottedLower
+	NameStrategy NameStrategy
+	// TimeLayout for encode time.Time value. default: DefaultTimeLayout
+	TimeLayout string
+	// EncodeHooks custom hooks for convert value to string, will be called before builtin hooks.
+	EncodeHooks []EncodeHookFunc
+	// KeepOrder encode the *Parser in file order, otherwise the map keys are sorted by name. default: false
+	//
+	// NOTE: *OrderedMap value is always encoded in it's order.
+	KeepOrder bool
+	// MaskSecrets mask the value of secret keys and the decrypted values on encode,
+	// for logging and review. default: false
+	//
+	// NOTE: on disabled, the unchanged decrypted value of ENC(...) will write back as the encrypted value.
+	MaskSecrets bool
+	// SecretKeys for check secret key on MaskSecrets. default: DefaultSecretKeys
+	SecretKeys []string
 	// comments map data. TODO
 	// key is path name, value is comments
 	// comments map[string]string
+
+	// entries metadata on encode a Parser, use for restore quotes.
+	entries map[string]*Entry
+	// hooks = EncodeHooks + builtin hooks
+	hooks []EncodeHookFunc
+	// first error on encode
+	err error
 }
 
 // NewEncoder instance.
@@ -32,7 +97,7 @@ func (e *Encoder) Marshal(v any) ([]byte, error) {
 	return e.Encode(v)
 }
 
-// Encode data(struct, map) to properties text
+// Encode data(struct, map, *Parser) to properties text
 func (e *Encoder) Encode(v any) ([]byte, error) {
 	if v == nil {
 		return nil, nil
@@ -46,40 +111,372 @@ func (e *Encoder) Encode(v any) ([]byte, error) {
 
 // Encode data(struct, map) to properties text
 func (e *Encoder) encode(v any) error {
+	// encode parsed data, will restore quotes for value
+	if p, ok := v.(*Parser); ok {
+		e.entries = p.entries
+		if e.KeepOrder {
+			v = p.OrderedData()
+		} else {
+			v = p.Data
+		}
+	}
+
+	e.hooks = make([]EncodeHookFunc, 0, len(e.EncodeHooks)+6)
+	e.hooks = append(e.hooks, e.EncodeHooks...)
+	e.hooks = append(e.hooks, EncodeHooks(e.TimeLayout)...)
+
+	if om, ok := v.(*OrderedMap); ok {
+		e.flatOrderedMap(om, "")
+		return e.err
+	}
+
 	rv := reflect.Indirect(reflect.ValueOf(v))
+	switch rv.Kind() {
+	case reflect.Struct:
+		e.flatStruct(rv, "")
+	case reflect.Map:
+		e.flatMap(rv, "")
+	default:
+		return errors.New("only allow encode map and struct data")
+	}
+	return e.err
+}
 
-	// convert struct to map[string]any
-	if rv.Kind() == reflect.Struct {
-		mp := make(map[string]any)
-		cfg := &mapstructure.DecoderConfig{
-			TagName: e.TagName,
-			Result:  &mp,
+func (e *Encoder) flatStruct(rv reflect.Value, parent string) {
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		sf := rt.Field(i)
+		if !sf.IsExported() {
+			continue
 		}
 
-		decoder, _ := mapstructure.NewDecoder(cfg)
-		if err := decoder.Decode(v); err != nil {
-			return err
+		ft := e.parseField(sf)
+		if ft.name == "-" {
+			continue
 		}
 
-		rv = reflect.ValueOf(mp)
-	} else if rv.Kind() != reflect.Map {
-		return errors.New("only allow encode map and struct data")
+		fv := rv.Field(i)
+		if ft.squash {
+			if fv = reflects.Indirect(fv); fv.Kind() == reflect.Struct {
+				e.flatStruct(fv, parent)
+			}
+			continue
+		}
+
+		name := ft.name
+		if parent != "" {
+			name = parent + "." + name
+		}
+		e.flatValue(fv, name, e.OmitEmpty || ft.omitEmpty)
 	}
+}
 
-	// TODO collect to map[string]string then sort keys
-	reflects.FlatMap(rv, e.writeln)
-	return nil
+// fieldTag info of the struct field
+type fieldTag struct {
+	name string
+	// embedded struct with tag option "squash"
+	squash bool
+	// has tag option "omitempty"
+	omitEmpty bool
 }
 
-func (e *Encoder) writeln(path string, rv reflect.Value) {
-	e.buf.WriteString(path)
-	e.buf.WriteByte('=')
+// parse key name and options for the struct field. eg: `properties:"name,omitempty"`
+func (e *Encoder) parseField(sf reflect.StructField) fieldTag {
+	name, opts, _ := strings.Cut(sf.Tag.Get(e.TagName), ",")
 
-	val := reflects.String(rv)
-	if rv.Kind() == reflect.String && strings.ContainsRune(val, '\n') {
-		val = strings.Replace(val, "\n", "\\\n", -1)
+	ft := fieldTag{name: name}
+	for _, opt := range strings.Split(opts, ",") {
+		switch opt {
+		case "squash":
+			ft.squash = sf.Anonymous
+		case "omitempty":
+			ft.omitEmpty = true
+		}
+	}
+
+	if ft.name == "" {
+		ft.name = sf.Name
+		if e.NameStrategy != nil {
+			ft.name = e.NameStrategy(sf.Name)
+		}
 	}
+	return ft
+}
+
+func (e *Encoder) flatMap(rv reflect.Value, parent string) {
+	keys := rv.MapKeys()
+	sort.Slice(keys, func(i, j int) bool {
+		return reflects.String(keys[i]) < reflects.String(keys[j])
+	})
+
+	for _, key := range keys {
+		path := reflects.String(key)
+		if parent != "" {
+			path = parent + "." + path
+		}
+		e.flatValue(rv.MapIndex(key), path, e.OmitEmpty)
+	}
+}
+
+func (e *Encoder) flatOrderedMap(om *OrderedMap, parent string) {
+	om.Range(func(key string, val any) bool {
+		path := key
+		if parent != "" {
+			path = parent + "." + path
+		}
+		e.flatValue(reflect.ValueOf(val), path, e.OmitEmpty)
+		return true
+	})
+}
+
+func (e *Encoder) flatSlice(rv reflect.Value, parent string) {
+	switch e.SliceStyle {
+	case SliceInline:
+		if !e.containsStruct(rv) {
+			e.writeRaw(parent, e.masked(parent, e.inlineValue(rv)))
+			return
+		}
+	case SliceComma:
+		if ss, ok := e.commaElems(rv); ok {
+			e.writeRaw(parent, e.masked(parent, strings.Join(ss, ",")))
+			return
+		}
+	}
+
+	for i := 0; i < rv.Len(); i++ {
+		e.flatValue(rv.Index(i), parent+"["+strconv.Itoa(i)+"]", false)
+	}
+}
+
+func (e *Encoder) flatValue(rv reflect.Value, path string, omitEmpty bool) {
+	if omitEmpty && isEmptyValue(rv) {
+		return
+	}
+
+	if om, ok := orderedMapOf(rv); ok {
+		if !omitEmpty || om.Len() > 0 {
+			e.flatOrderedMap(om, path)
+		}
+		return
+	}
+
+	rv, str, ok := e.resolve(rv)
+	if ok {
+		e.writeln(path, str)
+		return
+	}
+
+	switch rv.Kind() {
+	case reflect.Invalid: // nil pointer or interface
+		switch e.NilStyle {
+		case NilEmpty:
+			e.writeRaw(path, "")
+		case NilKeyOnly:
+			e.buf.WriteString(path)
+			e.buf.WriteByte('\n')
+		}
+	case reflect.Struct:
+		e.flatStruct(rv, path)
+	case reflect.Map:
+		e.flatMap(rv, path)
+	case reflect.Slice, reflect.Array:
+		e.flatSlice(rv, path)
+	default:
+		e.writeln(path, reflects.String(rv))
+	}
+}
 
+// resolve the value by encode hooks, will unwrap pointer and interface value.
+//
+// Returns ok=true on the value is converted to string by hooks,
+// returns invalid value on the value is nil.
+func (e *Encoder) resolve(rv reflect.Value) (_ reflect.Value, str string, ok bool) {
+	for rv.IsValid() {
+		kind := rv.Kind()
+		if (kind == reflect.Ptr || kind == reflect.Interface) && rv.IsNil() {
+			return reflect.Value{}, "", false
+		}
+
+		if kind != reflect.Interface {
+			for _, hook := range e.hooks {
+				str, ok, err := hook(rv)
+				if err != nil {
+					if e.err == nil {
+						e.err = err
+					}
+					return rv, "", true
+				}
+				if ok {
+					return rv, str, true
+				}
+			}
+		}
+
+		switch kind {
+		case reflect.Ptr, reflect.Interface:
+			rv = rv.Elem()
+		case reflect.Slice:
+			// []byte as string
+			if rv.Type().Elem().Kind() == reflect.Uint8 {
+				return rv, string(rv.Bytes()), true
+			}
+			return rv, "", false
+		default:
+			return rv, "", false
+		}
+	}
+	return rv, "", false
+}
+
+func (e *Encoder) writeln(path, val string) {
+	// keep the encrypted value. eg: ENC(base64...)
+	if entry, ok := e.entries[path]; ok && entry.Encrypted() && entry.Value == val && !e.MaskSecrets {
+		e.writeRaw(path, entry.cipher)
+		return
+	}
+
+	val = e.masked(path, val)
+	if q := e.quoteStyle(path, val); q != QuoteNone {
+		val = quoteValue(val, q)
+	}
+
+	e.writeRaw(path, val)
+}
+
+// mask the value of secret key or decrypted value on MaskSecrets is enabled.
+func (e *Encoder) masked(path, val string) string {
+	if !e.MaskSecrets {
+		return val
+	}
+
+	if entry, ok := e.entries[path]; ok && entry.Encrypted() || IsSecretKey(path, e.SecretKeys...) {
+		return MaskValue(val)
+	}
+	return val
+}
+
+func (e *Encoder) writeRaw(path, val string) {
+	e.buf.WriteString(path)
+	e.buf.WriteByte('=')
 	e.buf.WriteString(val)
 	e.buf.WriteByte('\n')
 }
+
+// get quote style for write the value. will keep the quote style of parsed entry.
+func (e *Encoder) quoteStyle(path, val string) byte {
+	if entry, ok := e.entries[path]; ok && entry.Quoted() {
+		return entry.Quote
+	}
+
+	if needQuote(val) {
+		return QuoteDouble
+	}
+	return QuoteNone
+}
+
+// unwrap the interface value, check it is a *OrderedMap.
+func orderedMapOf(rv reflect.Value) (*OrderedMap, bool) {
+	for rv.Kind() == reflect.Interface && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+
+	if rv.Kind() == reflect.Ptr && !rv.IsNil() && rv.CanInterface() {
+		om, ok := rv.Interface().(*OrderedMap)
+		return om, ok
+	}
+	return nil, false
+}
+
+// check the value is struct or contains struct element.
+func (e *Encoder) containsStruct(rv reflect.Value) bool {
+	rv, _, ok := e.resolve(rv)
+	if ok {
+		return false
+	}
+
+	switch rv.Kind() {
+	case reflect.Struct:
+		return true
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			if e.containsStruct(rv.Index(i)) {
+				return true
+			}
+		}
+	case reflect.Map:
+		for _, key := range rv.MapKeys() {
+			if e.containsStruct(rv.MapIndex(key)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// collect scalar elements for comma style. ok is false on element is not scalar or contains comma.
+func (e *Encoder) commaElems(rv reflect.Value) (ss []string, ok bool) {
+	ss = make([]string, rv.Len())
+	for i := 0; i < rv.Len(); i++ {
+		ev, str, ok := e.resolve(rv.Index(i))
+		if !ok {
+			switch ev.Kind() {
+			case reflect.Struct, reflect.Map, reflect.Slice, reflect.Array, reflect.Invalid:
+				return nil, false
+			}
+			str = reflects.String(ev)
+		}
+
+		if str != strings.TrimSpace(str) || strings.ContainsAny(str, ",\n") {
+			return nil, false
+		}
+		ss[i] = str
+	}
+	return ss, true
+}
+
+// format slice, map value to inline literal string. eg: [a, b]
+func (e *Encoder) inlineValue(rv reflect.Value) string {
+	rv, str, ok := e.resolve(rv)
+	if ok {
+		return quoteInlineStr(str)
+	}
+
+	switch rv.Kind() {
+	case reflect.Invalid:
+		return "null"
+	case reflect.Slice, reflect.Array:
+		ss := make([]string, rv.Len())
+		for i := 0; i < rv.Len(); i++ {
+			ss[i] = e.inlineValue(rv.Index(i))
+		}
+		return "[" + strings.Join(ss, ", ") + "]"
+	case reflect.Map:
+		keys := rv.MapKeys()
+		ss := make([]string, 0, len(keys))
+		for _, key := range keys {
+			ss = append(ss, quoteInlineStr(reflects.String(key))+": "+e.inlineValue(rv.MapIndex(key)))
+		}
+		sort.Strings(ss)
+		return "{" + strings.Join(ss, ", ") + "}"
+	case reflect.String:
+		return quoteInlineStr(rv.String())
+	default:
+		return reflects.String(rv)
+	}
+}
+
+// check the value is zero value, nil or empty map, slice.
+func isEmptyValue(rv reflect.Value) bool {
+	switch rv.Kind() {
+	case reflect.Invalid:
+		return true
+	case reflect.Map, reflect.Slice, reflect.String:
+		return rv.Len() == 0
+	case reflect.Ptr:
+		return rv.IsNil()
+	case reflect.Interface:
+		return rv.IsNil() || isEmptyValue(rv.Elem())
+	default:
+		return rv.IsZero()
+	}
+}
@@ -2,6 +2,12 @@ package properties_test
 
 import (
 	"fmt"
+	"net"
+	"net/netip"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strconv"
 	"testing"
 	"time"
 
@@ -28,7 +34,15 @@ func TestEncode(t *testing.T) {
 	assert.NotEmpty(t, bs)
 	assert.StrContains(t, str, "name=inhere")
 	assert.StrContains(t, str, "top.sub1[0]=val1-0")
-	assert.StrContains(t, str, "str2=a multi \\\nline string")
+	assert.StrContains(t, str, `str2="a multi \nline string"`)
+
+	// the newlines are kept on parse back
+	bs, err = properties.Encode(map[string]string{"k": "line1\nline2", "k2": "a\r\nb"})
+	assert.NoErr(t, err)
+	p, err := properties.Parse(string(bs))
+	assert.NoErr(t, err)
+	assert.Eq(t, "line1\nline2", p.Str("k"))
+	assert.Eq(t, "a\r\nb", p.Str("k2"))
 
 	bs, err = properties.Marshal(nil)
 	assert.NoErr(t, err)
@@ -53,7 +67,7 @@ func TestEncode_struct(t *testing.T) {
 	str := string(bs)
 	assert.StrContains(t, str, "name=inhere")
 	assert.StrContains(t, str, "age=234")
-	assert.StrContains(t, str, "expire=3000000000")
+	assert.StrContains(t, str, "expire=3s")
 }
 
 func TestEncode_error(t *testing.T) {
@@ -61,3 +75,314 @@ func TestEncode_error(t *testing.T) {
 	assert.Nil(t, bs)
 	assert.ErrMsg(t, err, "only allow encode map and struct data")
 }
+
+func TestEncode_quoteValue(t *testing.T) {
+	bs, err := properties.Encode(map[string]any{
+		"str0": "normal value",
+		"str1": "  leading spaces",
+		"str2": "#not comment",
+		"str3": "has \"quote\" and # char",
+		"str4": `end with \`,
+	})
+	assert.NoErr(t, err)
+
+	str := string(bs)
+	fmt.Println(str)
+	assert.StrContains(t, str, "str0=normal value\n")
+	assert.StrContains(t, str, `str1="  leading spaces"`)
+	assert.StrContains(t, str, `str2="#not comment"`)
+	assert.StrContains(t, str, `str3="has \"quote\" and # char"`)
+	assert.StrContains(t, str, `str4="end with \\"`)
+
+	// decode the encoded text
+	p, err := properties.Parse(str)
+	assert.NoErr(t, err)
+	assert.Eq(t, "  leading spaces", p.Str("str1"))
+	assert.Eq(t, "#not comment", p.Str("str2"))
+	assert.Eq(t, "has \"quote\" and # char", p.Str("str3"))
+	assert.Eq(t, `end with \`, p.Str("str4"))
+}
+
+func TestEncode_parser(t *testing.T) {
+	p, err := properties.Parse(`
+key0 = value0
+key1 = "value1"
+key2 = 'value2'
+`)
+	assert.NoErr(t, err)
+
+	bs, err := properties.Encode(p)
+	assert.NoErr(t, err)
+
+	str := string(bs)
+	assert.StrContains(t, str, "key0=value0\n")
+	assert.StrContains(t, str, `key1="value1"`)
+	assert.StrContains(t, str, `key2='value2'`)
+}
+
+func TestEncode_inlineSlice(t *testing.T) {
+	data := map[string]any{
+		"ids":  []int{23, 34},
+		"strs": []string{"a", "b, c", ""},
+		"top": map[string]any{
+			"list": []any{"a", []int{1, 2}, map[string]any{"k": "v"}},
+		},
+	}
+
+	e := properties.NewEncoder()
+	e.SliceStyle = properties.SliceInline
+	bs, err := e.Encode(data)
+	assert.NoErr(t, err)
+
+	str := string(bs)
+	fmt.Println(str)
+	assert.StrContains(t, str, "ids=[23, 34]\n")
+	assert.StrContains(t, str, `strs=[a, "b, c", ""]`)
+	assert.StrContains(t, str, "top.list=[a, [1, 2], {k: v}]\n")
+
+	// parse back
+	p := properties.NewParser(properties.ParseInlineSlice)
+	assert.NoErr(t, p.ParseBytes(bs))
+	assert.Eq(t, []string{"23", "34"}, p.Strings("ids"))
+	assert.Eq(t, []any{"a", "b, c", ""}, p.Get("strs"))
+	assert.Eq(t, []any{"a", []any{"1", "2"}, map[string]any{"k": "v"}}, p.Get("top.list"))
+}
+
+func TestEncode_sliceStyle(t *testing.T) {
+	type User struct {
+		Name string `properties:"name"`
+		Age  int    `properties:"age"`
+	}
+
+	type MyConf struct {
+		Name  string   `properties:"name"`
+		Tags  []string `properties:"tags"`
+		Ports []int    `properties:"ports"`
+		Users []User   `properties:"users"`
+	}
+
+	myc := &MyConf{
+		Name:  "inhere",
+		Tags:  []string{"a", "b"},
+		Ports: []int{80, 443},
+		Users: []User{{Name: "tom", Age: 23}, {Name: "john", Age: 34}},
+	}
+
+	tests := []struct {
+		style properties.SliceStyle
+		want  []string
+	}{
+		{properties.SliceIndexed, []string{"tags[0]=a\n", "tags[1]=b\n", "ports[1]=443\n", "users[0].name=tom\n", "users[1].age=34\n"}},
+		{properties.SliceComma, []string{"tags=a,b\n", "ports=80,443\n", "users[0].name=tom\n", "users[1].age=34\n"}},
+		{properties.SliceInline, []string{"tags=[a, b]\n", "ports=[80, 443]\n", "users[0].name=tom\n", "users[1].age=34\n"}},
+	}
+
+	for _, tt := range tests {
+		e := properties.NewEncoder()
+		e.SliceStyle = tt.style
+		bs, err := e.Encode(myc)
+		assert.NoErr(t, err)
+
+		str := string(bs)
+		for _, want := range tt.want {
+			assert.StrContains(t, str, want)
+		}
+
+		// encode then parse, should be same
+		p := properties.NewParser(properties.ParseInlineSlice)
+		assert.NoErr(t, p.ParseBytes(bs))
+
+		cfg := &MyConf{}
+		assert.NoErr(t, p.Decode(cfg))
+		assert.Eq(t, myc, cfg)
+	}
+}
+
+type textLevel int
+
+func (l textLevel) MarshalText() ([]byte, error) {
+	if l == 3 {
+		return []byte("WARN"), nil
+	}
+	return []byte("INFO"), nil
+}
+
+func (l *textLevel) UnmarshalText(text []byte) error {
+	if string(text) == "WARN" {
+		*l = 3
+	} else {
+		*l = 1
+	}
+	return nil
+}
+
+func TestEncode_hooks(t *testing.T) {
+	type Sub struct {
+		Name string `properties:"name"`
+	}
+
+	type MyConf struct {
+		Expire  time.Duration   `properties:"expire"`
+		StartAt time.Time       `properties:"start-at"`
+		Home    *url.URL        `properties:"home"`
+		IP      net.IP          `properties:"ip"`
+		Level   textLevel       `properties:"level"`
+		Pattern *regexp.Regexp  `properties:"pattern"`
+		Timeout []time.Duration `properties:"timeout"`
+		NilSub  *Sub            `properties:"nil-sub"`
+		NilAny  any             `properties:"nil-any"`
+	}
+
+	home, _ := url.Parse("https://github.com/gookit/properties")
+	myc := &MyConf{
+		Expire:  3 * time.Second,
+		StartAt: time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC),
+		Home:    home,
+		IP:      net.ParseIP("127.0.0.1"),
+		Level:   3,
+		Pattern: regexp.MustCompile(`^app-\d+$`),
+		Timeout: []time.Duration{time.Second, time.Minute},
+	}
+
+	bs, err := properties.Encode(myc)
+	assert.NoErr(t, err)
+
+	str := string(bs)
+	fmt.Println(str)
+	assert.StrContains(t, str, "expire=3s\n")
+	assert.StrContains(t, str, "start-at=2024-01-02T15:04:05Z\n")
+	assert.StrContains(t, str, "home=https://github.com/gookit/properties\n")
+	assert.StrContains(t, str, "ip=127.0.0.1\n")
+	assert.StrContains(t, str, "level=WARN\n")
+	assert.StrContains(t, str, `pattern=^app-\d+$`)
+	assert.StrContains(t, str, "timeout[1]=1m0s\n")
+	assert.NotContains(t, str, "nil-sub")
+	assert.NotContains(t, str, "nil-any")
+
+	// decode and re-encode
+	cfg := &MyConf{}
+	assert.NoErr(t, properties.Unmarshal(bs, cfg))
+	assert.Eq(t, myc.Expire, cfg.Expire)
+	assert.Eq(t, textLevel(3), cfg.Level)
+	assert.True(t, myc.StartAt.Equal(cfg.StartAt))
+
+	bs2, err := properties.Encode(cfg)
+	assert.NoErr(t, err)
+	assert.StrContains(t, string(bs2), "expire=3s\n")
+	assert.StrContains(t, string(bs2), "level=WARN\n")
+
+	// custom hook
+	e := properties.NewEncoder()
+	e.EncodeHooks = append(e.EncodeHooks, func(rv reflect.Value) (string, bool, error) {
+		if rv.Type() == reflect.TypeOf(textLevel(0)) {
+			return "level-" + strconv.Itoa(int(rv.Int())), true, nil
+		}
+		return "", false, nil
+	})
+
+	bs, err = e.Encode(map[string]any{"level": textLevel(3)})
+	assert.NoErr(t, err)
+	assert.Eq(t, "level=level-3\n", string(bs))
+}
+
+func TestEncode_zeroIP(t *testing.T) {
+	type MyConf struct {
+		IP     net.IP       `properties:"ip"`
+		Addr   netip.Addr   `properties:"addr"`
+		Net    net.IPNet    `properties:"net"`
+		Prefix netip.Prefix `properties:"prefix"`
+	}
+
+	bs, err := properties.Encode(&MyConf{})
+	assert.NoErr(t, err)
+	assert.Eq(t, "ip=\naddr=\nnet=\nprefix=\n", string(bs))
+
+	// zero value can round-trip
+	cfg := &MyConf{}
+	assert.NoErr(t, properties.Unmarshal(bs, cfg))
+	assert.Nil(t, cfg.IP)
+	assert.False(t, cfg.Addr.IsValid())
+}
+
+func TestEncode_omitEmpty(t *testing.T) {
+	type Sub struct {
+		Host string `properties:"host"`
+		Port int    `properties:"port"`
+	}
+
+	type MyConf struct {
+		Name    string            `properties:"name"`
+		Age     int               `properties:"age,omitempty"`
+		Tags    []string          `properties:"tags"`
+		Expire  time.Duration     `properties:"expire"`
+		Sub     Sub               `properties:"sub"`
+		SubPtr  *Sub              `properties:"sub-ptr"`
+		Labels  map[string]string `properties:"labels"`
+		Enabled bool              `properties:"enabled"`
+	}
+
+	myc := &MyConf{Name: "inhere", Sub: Sub{Port: 8080}}
+
+	// only tag option
+	bs, err := properties.Encode(myc)
+	assert.NoErr(t, err)
+	str := string(bs)
+	assert.NotContains(t, str, "age=")
+	assert.StrContains(t, str, "expire=0s\n")
+	assert.StrContains(t, str, "sub.host=\n")
+	assert.StrContains(t, str, "enabled=false\n")
+
+	// global option
+	e := properties.NewEncoder()
+	e.OmitEmpty = true
+	bs, err = e.Encode(myc)
+	assert.NoErr(t, err)
+	assert.Eq(t, "name=inhere\nsub.port=8080\n", string(bs))
+
+	e = properties.NewEncoder()
+	e.OmitEmpty = true
+	bs, err = e.Encode(map[string]any{
+		"name":  "inhere",
+		"zero":  0,
+		"empty": map[string]any{"sub": ""},
+		"list":  []any{},
+		"nil":   nil,
+	})
+	assert.NoErr(t, err)
+	assert.Eq(t, "name=inhere\n", string(bs))
+}
+
+func TestEncode_nilStyle(t *testing.T) {
+	type Sub struct {
+		Host string `properties:"host"`
+	}
+
+	data := map[string]any{
+		"name": "inhere",
+		"sub":  (*Sub)(nil),
+		"any":  nil,
+	}
+
+	tests := []struct {
+		style properties.NilStyle
+		want  string
+	}{
+		{properties.NilSkip, "name=inhere\n"},
+		{properties.NilEmpty, "any=\nname=inhere\nsub=\n"},
+		{properties.NilKeyOnly, "any\nname=inhere\nsub\n"},
+	}
+
+	for _, tt := range tests {
+		e := properties.NewEncoder()
+		e.NilStyle = tt.style
+		bs, err := e.Encode(data)
+		assert.NoErr(t, err)
+		assert.Eq(t, tt.want, string(bs))
+
+		// can be parsed back
+		p, err := properties.Parse(string(bs), properties.ParseBareKey)
+		assert.NoErr(t, err)
+		assert.Eq(t, "inhere", p.Str("name"))
+		assert.Eq(t, tt.style != properties.NilSkip, p.Has("any"))
+	}
+}
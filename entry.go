@@ -0,0 +1,149 @@
+package properties
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+// quote style constants for the value
+const (
+	QuoteNone   byte = 0
+	QuoteSingle byte = '\''
+	QuoteDouble byte = '"'
+)
+
+// Entry metadata of a parsed key-value item
+type Entry struct {
+	// Key path name. eg: top.sub.key
+	Key string
+	// Value raw string value, quotes are removed and escapes are processed.
+	Value string
+	// Comment for the key
+	Comment string
+	// Quote char of the value. allow: QuoteNone, QuoteSingle, QuoteDouble
+	Quote byte
+	// Line number of the key in the source, start from 1. is 0 on not from source text.
+	Line int
+	// end line number of the multi line value
+	endLine int
+	// raw encrypted value. eg: ENC(base64...)
+	cipher string
+}
+
+// Quoted check the value is quoted
+func (e *Entry) Quoted() bool {
+	return e.Quote != QuoteNone
+}
+
+// Encrypted check the value is decrypted from ENC(...) value
+func (e *Entry) Encrypted() bool {
+	return e.cipher != ""
+}
+
+// unescapeValue process escape chars in double-quoted value. eg: \" \n \t \\ \u00e9
+func unescapeValue(s string) string {
+	if !strings.ContainsRune(s, '\\') {
+		return s
+	}
+
+	var sb strings.Builder
+	sb.Grow(len(s))
+
+	ln := len(s)
+	for i := 0; i < ln; i++ {
+		c := s[i]
+		if c != '\\' || i == ln-1 {
+			sb.WriteByte(c)
+			continue
+		}
+
+		i++
+		switch s[i] {
+		case 'n':
+			sb.WriteByte('\n')
+		case 't':
+			sb.WriteByte('\t')
+		case 'r':
+			sb.WriteByte('\r')
+		case '"', '\'', '\\':
+			sb.WriteByte(s[i])
+		case 'u':
+			r, n := unquoteUnicode(s[i-1:])
+			if n == 0 { // invalid, keep as is
+				sb.WriteString(`\u`)
+				continue
+			}
+			sb.WriteRune(r)
+			i += n - 2
+		default: // keep unknown escape
+			sb.WriteByte('\\')
+			sb.WriteByte(s[i])
+		}
+	}
+	return sb.String()
+}
+
+// unquoteUnicode decode the leading unicode escape of s, returns the rune and the length of the escape.
+// the UTF-16 surrogate pair is also supported. eg: \u00e9, \ud83d\ude00
+func unquoteUnicode(s string) (rune, int) {
+	r, ok := hexRune(s)
+	if !ok {
+		return 0, 0
+	}
+
+	if utf16.IsSurrogate(r) {
+		low, ok := hexRune(s[6:])
+		if r = utf16.DecodeRune(r, low); !ok || r == '\uFFFD' {
+			return 0, 0
+		}
+		return r, 12
+	}
+	return r, 6
+}
+
+// parse the rune of the "\uXXXX" at the start of s
+func hexRune(s string) (rune, bool) {
+	if len(s) < 6 || s[0] != '\\' || s[1] != 'u' {
+		return 0, false
+	}
+
+	code, err := strconv.ParseUint(s[2:6], 16, 16)
+	return rune(code), err == nil
+}
+
+// escapeValue for write to double-quoted value
+func escapeValue(s string) string {
+	return valEscaper.Replace(s)
+}
+
+var valEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\t", `\t`, "\r", `\r`)
+
+// needQuote check the value need quote on encode.
+//
+// eg: has leading or trailing spaces, contains comment chars or newlines, starts with quote char.
+func needQuote(s string) bool {
+	ln := len(s)
+	if ln == 0 {
+		return false
+	}
+
+	switch s[0] {
+	case ' ', '\t', '"', '\'', '#', '!':
+		return true
+	}
+	if last := s[ln-1]; last == ' ' || last == '\t' || last == '\\' {
+		return true
+	}
+	return strings.Contains(s, " #") || strings.Contains(s, " //") || strings.ContainsAny(s, "\t\n\r")
+}
+
+// quoteValue quote the value by quote char.
+//
+// NOTE: single-quoted value not support escape, will fallback to double quote.
+func quoteValue(s string, quote byte) string {
+	if quote == QuoteSingle && !strings.ContainsAny(s, "'\n\r") {
+		return "'" + s + "'"
+	}
+	return `"` + escapeValue(s) + `"`
+}
@@ -0,0 +1,186 @@
+package properties
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/gookit/goutil/envutil"
+)
+
+// EnvKeyMapper convert the env var name(prefix removed) to the key path.
+//
+// eg: SERVER_PORT -> server.port
+type EnvKeyMapper func(name string) string
+
+// DefaultEnvKeyMapper map "_" to ".", "__" to "-". eg:
+//
+//	SERVER_PORT -> server.port
+//	SERVER_MAX__WAIT -> server.max-wait
+//	LIST_0_ -> list[0]
+//	USERS_0_NAME -> users[0].name
+var DefaultEnvKeyMapper = NewEnvKeyMapper(".", "-")
+
+// NewEnvKeyMapper create an EnvKeyMapper. sep is replacement for "_", dblSep is replacement for "__".
+//
+// Number node will be as slice index on sep is ".". eg: LIST_0 -> list[0]
+func NewEnvKeyMapper(sep, dblSep string) EnvKeyMapper {
+	return func(name string) string {
+		var sb strings.Builder
+		parts := strings.Split(strings.ToLower(name), "__")
+		for i, part := range parts {
+			if i > 0 {
+				sb.WriteString(dblSep)
+			}
+
+			nodes := strings.Split(part, "_")
+			for j, node := range nodes {
+				if node == "" {
+					continue
+				}
+
+				if sep == "." && isDigits(node) && sb.Len() > 0 {
+					sb.WriteString("[" + node + "]")
+					continue
+				}
+
+				if j > 0 && sb.Len() > 0 {
+					sb.WriteString(sep)
+				}
+				sb.WriteString(node)
+			}
+		}
+		return sb.String()
+	}
+}
+
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// OverlayEnv overlay the env vars that start with the prefix onto keys.
+//
+// The existing key will be matched by ignore case and separators, otherwise
+// will use Options.EnvKeyMapper to convert the env name to new key.
+// The EnvKeyMapper is also used on multi keys are matched, like "a.bc" and "ab.c". eg:
+//
+//	// prefix: APP_
+//	APP_SERVER_PORT=9090      -> server.port
+//	APP_SERVER_MAX_WAIT=3s    -> server.max-wait (if exists)
+//	APP_LIST_0_=a             -> list[0]
+func (p *Parser) OverlayEnv(prefix string) error {
+	return p.OverlayEnvMap(prefix, envutil.Environ())
+}
+
+// OverlayEnvMap overlay the env map onto keys. see OverlayEnv
+func (p *Parser) OverlayEnvMap(prefix string, envs map[string]string) error {
+	for _, kv := range p.envPairs(prefix, envs) {
+		p.overlayValue(kv.key, kv.value)
+		if p.err != nil {
+			return p.err
+		}
+	}
+	return nil
+}
+
+// kvPair of the overlay value
+type kvPair struct {
+	key, value string
+	// source name of the value. eg: env var name
+	src string
+}
+
+// envPairs resolve the key path for the env vars that start with the prefix.
+func (p *Parser) envPairs(prefix string, envs map[string]string) []kvPair {
+	mapper := p.opts.EnvKeyMapper
+	if mapper == nil {
+		mapper = DefaultEnvKeyMapper
+	}
+
+	names := make([]string, 0, len(envs))
+	for name := range envs {
+		if len(name) > len(prefix) && strings.HasPrefix(name, prefix) {
+			names = append(names, name)
+		}
+	}
+	// make overlay order is stable
+	sort.Strings(names)
+
+	exists := p.envKeyIndex()
+	pairs := make([]kvPair, 0, len(names))
+	for _, name := range names {
+		sub := strings.TrimPrefix(name, prefix)
+		key, ok := exists[envMatchKey(sub)]
+		// not exists or the match is ambiguous
+		if !ok || key == "" {
+			if key = mapper(sub); key == "" {
+				continue
+			}
+		}
+		pairs = append(pairs, kvPair{key: key, value: envs[name], src: name})
+	}
+	return pairs
+}
+
+// overlay a raw string value onto the key, will override the exists value.
+func (p *Parser) overlayValue(key, value string) {
+	entry, ok := p.entries[key]
+	if !ok {
+		entry = &Entry{Key: key}
+		p.entries[key] = entry
+	}
+	entry.Value = value
+	entry.Quote = QuoteNone
+	entry.cipher = ""
+	p.smap[key] = value
+
+	var setVal any = value
+	if p.opts.InlineSlice {
+		if val, ok := p.parseInline(value); ok {
+			setVal = val
+		}
+	}
+
+	if p.opts.InferType {
+		if str, ok := setVal.(string); ok {
+			setVal = inferValue(str)
+		}
+	}
+
+	if p.opts.BeforeCollect != nil {
+		setVal = p.opts.BeforeCollect(key, setVal)
+	}
+	p.collect(key, setVal)
+}
+
+// build index for match exists keys by env name. the value is empty on multi keys are matched.
+func (p *Parser) envKeyIndex() map[string]string {
+	idx := make(map[string]string, len(p.smap))
+	for key := range p.smap {
+		mk := envMatchKey(key)
+		if _, ok := idx[mk]; ok {
+			idx[mk] = ""
+		} else {
+			idx[mk] = key
+		}
+	}
+	return idx
+}
+
+// envMatchKey remove separators and convert to lower. eg: server.max-wait -> servermaxwait
+func envMatchKey(s string) string {
+	var sb strings.Builder
+	sb.Grow(len(s))
+	for _, r := range strings.ToLower(s) {
+		switch r {
+		case '.', '-', '_', '[', ']':
+		default:
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,96 @@
+package properties_test
+
+import (
+	"testing"
+
+	"github.com/gookit/goutil/testutil"
+	"github.com/gookit/goutil/testutil/assert"
+	"github.com/gookit/properties"
+)
+
+func TestEnvKeyMapper(t *testing.T) {
+	fn := properties.DefaultEnvKeyMapper
+	assert.Eq(t, "server.port", fn("SERVER_PORT"))
+	assert.Eq(t, "server.max-wait", fn("SERVER_MAX__WAIT"))
+	assert.Eq(t, "list[0]", fn("LIST_0_"))
+	assert.Eq(t, "users[1].name", fn("USERS_1_NAME"))
+
+	fn = properties.NewEnvKeyMapper("-", ".")
+	assert.Eq(t, "server.max-wait", fn("SERVER__MAX_WAIT"))
+	assert.Eq(t, "list-0", fn("LIST_0"))
+}
+
+func TestParser_OverlayEnv(t *testing.T) {
+	text := `
+name = demo
+server.port = 8080
+server.max-wait = 1s
+list[0] = a
+list[1] = b
+`
+	envs := map[string]string{
+		"APP_SERVER_PORT":     "9090",
+		"APP_SERVER_MAX_WAIT": "3s",
+		"APP_SERVER_HOST":     "127.0.0.1",
+		"APP_LIST_1_":         "c",
+		"APP_USERS_0_NAME":    "tom",
+		"OTHER_NAME":          "other",
+	}
+
+	p := properties.NewParser()
+	assert.NoErr(t, p.Parse(text))
+	assert.NoErr(t, p.OverlayEnvMap("APP_", envs))
+
+	assert.Eq(t, "demo", p.Str("name"))
+	assert.Eq(t, "9090", p.Str("server.port"))
+	assert.Eq(t, "3s", p.Str("server.max-wait"))
+	assert.Eq(t, "127.0.0.1", p.Str("server.host"))
+	assert.Eq(t, "c", p.Str("list.1"))
+	assert.Eq(t, "tom", p.Str("users.0.name"))
+	assert.Eq(t, "9090", p.SMap().Get("server.port"))
+
+	type Server struct {
+		Host    string
+		Port    int
+		MaxWait string `properties:"max-wait"`
+	}
+	s := &Server{}
+	assert.NoErr(t, p.MapStruct("server", s))
+	assert.Eq(t, 9090, s.Port)
+	assert.Eq(t, "127.0.0.1", s.Host)
+	assert.Eq(t, "3s", s.MaxWait)
+}
+
+func TestParser_OverlayEnv_ambiguous(t *testing.T) {
+	p := properties.NewParser()
+	assert.NoErr(t, p.Parse("a.bc = 1\nab.c = 2\nmax-wait = 3\n"))
+	assert.NoErr(t, p.OverlayEnvMap("APP_", map[string]string{
+		"APP_AB_C":    "X",
+		"APP_MAXWAIT": "5",
+	}))
+
+	// ambiguous match, use the EnvKeyMapper
+	assert.Eq(t, "1", p.Str("a.bc"))
+	assert.Eq(t, "X", p.Str("ab.c"))
+	// unique match
+	assert.Eq(t, "5", p.Str("max-wait"))
+	assert.False(t, p.Has("maxwait"))
+}
+
+func TestOptions_EnvPrefix(t *testing.T) {
+	testutil.MockEnvValues(map[string]string{
+		"APP_SERVER_PORT": "9090",
+		"APP_DEBUG":       "true",
+	}, func() {
+		p, err := properties.Parse("server.port = 8080\ndebug = false", properties.WithEnvOverlay("APP_"), properties.InferType)
+		assert.NoErr(t, err)
+		assert.Eq(t, int64(9090), p.Get("server.port"))
+		assert.Eq(t, true, p.Get("debug"))
+
+		// custom mapper
+		p, err = properties.Parse("server-port = 8080", properties.WithEnvOverlay("APP_", properties.NewEnvKeyMapper("-", ".")))
+		assert.NoErr(t, err)
+		assert.Eq(t, "9090", p.Str("server-port"))
+		assert.Eq(t, "true", p.Str("debug"))
+	})
+}
@@ -0,0 +1,308 @@
+package properties
+
+import (
+	"encoding"
+	"errors"
+	"flag"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/go-viper/mapstructure/v2"
+	"github.com/gookit/goutil/reflects"
+)
+
+// OverlayArgs overlay the command line args onto keys, returns the remaining args.
+//
+// Allow arg formats:
+//
+//	--server.port=9090
+//	-Dserver.port=9090
+//
+// Other args will be returned as remaining, and will stop parse on meet "--".
+func (p *Parser) OverlayArgs(args []string) ([]string, error) {
+	pairs, remain, err := parseArgs(args)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, kv := range pairs {
+		p.overlayValue(kv.key, kv.value)
+		if p.err != nil {
+			return nil, p.err
+		}
+	}
+	return remain, nil
+}
+
+// parse the property args. see Parser.OverlayArgs
+func parseArgs(args []string) (pairs []kvPair, remain []string, err error) {
+	for i, arg := range args {
+		if arg == "--" {
+			return pairs, append(remain, args[i+1:]...), nil
+		}
+
+		var kv string
+		if strings.HasPrefix(arg, "--") || strings.HasPrefix(arg, "-D") {
+			kv = arg[2:]
+		}
+
+		key, val, ok := strings.Cut(kv, "=")
+		if !ok {
+			if strings.HasPrefix(arg, "-D") {
+				return nil, nil, errors.New("invalid property arg: " + arg)
+			}
+			remain = append(remain, arg)
+			continue
+		}
+
+		if key = strings.TrimSpace(key); key == "" {
+			return nil, nil, errors.New("invalid property arg: " + arg)
+		}
+		pairs = append(pairs, kvPair{key: key, value: val, src: arg})
+	}
+	return pairs, remain, nil
+}
+
+var textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+
+// BindFlags register a flag for each key of the struct to the flag.FlagSet.
+//
+// The struct will be decoded from the parsed data first. The default value of
+// the flag is the parsed value, and usage is the comments of the key.
+//
+// On fs.Parse(), the flag value will be set to the struct field and the Parser. eg:
+//
+//	p.BindFlags(fs, cfg)
+//	fs.Parse([]string{"--server.port=9090"})
+func (p *Parser) BindFlags(fs *flag.FlagSet, ptr any) error {
+	rv := reflect.ValueOf(ptr)
+	if rv.Kind() != reflect.Ptr || rv.Elem().Kind() != reflect.Struct {
+		return errors.New("must be provide a struct ptr for bind flags")
+	}
+
+	if err := p.Decode(ptr); err != nil {
+		return err
+	}
+
+	// match the parsed key by the same rule of decoder
+	matchName := p.opts.makeDecoderConfig().MatchName
+	if matchName == nil {
+		matchName = strings.EqualFold
+	}
+	return p.bindFlags(fs, rv.Elem(), "", matchName)
+}
+
+func (p *Parser) bindFlags(fs *flag.FlagSet, rv reflect.Value, parent string, matchName func(mapKey, fieldName string) bool) error {
+	rt := rv.Type()
+	tagName := p.opts.tagName()
+
+	for i := 0; i < rt.NumField(); i++ {
+		sf := rt.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+
+		name, opts, _ := strings.Cut(sf.Tag.Get(tagName), ",")
+		if name == "-" {
+			continue
+		}
+
+		fv := rv.Field(i)
+		if sf.Anonymous && strings.Contains(opts, "squash") {
+			if isNestedStruct(fv) {
+				if err := p.bindFlags(fs, reflects.Indirect(fv), parent, matchName); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		// untagged field, use the name strategy or lower case. eg: "Host" -> "host"
+		if name == "" {
+			if p.opts.NameStrategy != nil {
+				name = p.opts.NameStrategy(sf.Name)
+			} else {
+				name = strings.ToLower(sf.Name)
+			}
+		}
+
+		key := p.flagKey(parent, name, matchName)
+		if isNestedStruct(fv) {
+			if err := p.bindFlags(fs, reflects.Indirect(fv), key, matchName); err != nil {
+				return err
+			}
+			continue
+		}
+
+		// skip map and slice of the struct
+		if !isFlagValue(fv.Type()) {
+			continue
+		}
+
+		// fs.Var() will panic on the flag redefined
+		if fs.Lookup(key) != nil {
+			return fmt.Errorf("flag %q is already defined", key)
+		}
+
+		ff := &fieldFlag{p: p, key: key, fv: fv}
+		ff.val = ff.defaultValue()
+		fs.Var(ff, key, commentText(p.comments[key]))
+	}
+	return nil
+}
+
+// flagKey use the parsed key on it's matched by matchName. eg: "server.host" -> "Server.HOST"
+func (p *Parser) flagKey(parent, name string, matchName func(mapKey, fieldName string) bool) string {
+	key, prefix := name, ""
+	if parent != "" {
+		key, prefix = parent+"."+name, parent+"."
+	}
+
+	if _, ok := p.smap[key]; ok {
+		return key
+	}
+
+	// the name may be dotted by the name strategy. eg: DottedLower
+	n := strings.Count(name, ".") + 1
+	for _, k := range p.keysByLine() {
+		if !strings.HasPrefix(k, prefix) {
+			continue
+		}
+
+		nodes := strings.SplitN(k[len(prefix):], ".", n+1)
+		if len(nodes) < n {
+			continue
+		}
+
+		sub := strings.Join(nodes[:n], ".")
+		if matchName(sub, name) {
+			return prefix + sub
+		}
+	}
+	return key
+}
+
+// struct field that should be bind as nested keys.
+func isNestedStruct(fv reflect.Value) bool {
+	if fv.Kind() == reflect.Ptr && fv.IsNil() {
+		return false
+	}
+	return isNestedType(reflects.Indirect(fv).Type())
+}
+
+// struct type that cannot be decoded from a string value.
+func isNestedType(typ reflect.Type) bool {
+	if typ.Kind() != reflect.Struct || typ == timeType || typ == urlType || typ == ipNetType {
+		return false
+	}
+	return !reflect.PointerTo(typ).Implements(textUnmarshalerType)
+}
+
+// check the type can be set by a flag string value.
+func isFlagValue(typ reflect.Type) bool {
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	switch typ.Kind() {
+	case reflect.Map, reflect.Interface, reflect.Func, reflect.Chan:
+		return false
+	case reflect.Struct:
+		return !isNestedType(typ)
+	case reflect.Slice, reflect.Array:
+		elem := typ.Elem()
+		return elem.Kind() != reflect.Map && !isNestedType(elem)
+	}
+	return true
+}
+
+// fieldFlag implement the flag.Value for a struct field
+type fieldFlag struct {
+	p   *Parser
+	key string
+	val string
+	// the struct field value
+	fv reflect.Value
+}
+
+// String get current value
+func (f *fieldFlag) String() string {
+	if f == nil {
+		return ""
+	}
+	return f.val
+}
+
+// Set value, will decode the value to the struct field and overlay onto the Parser.
+func (f *fieldFlag) Set(s string) error {
+	// decode to new value, avoid merge with the old slice elements
+	newVal := reflect.New(f.fv.Type())
+
+	decConf := f.p.opts.makeDecoderConfig()
+	decConf.Result = newVal.Interface()
+	decoder, err := mapstructure.NewDecoder(decConf)
+	if err == nil {
+		err = decoder.Decode(s)
+	}
+	if err != nil {
+		return err
+	}
+
+	f.fv.Set(newVal.Elem())
+	f.val = s
+	f.p.overlayValue(f.key, s)
+	return f.p.err
+}
+
+// IsBoolFlag allow use "--debug" for bool field
+func (f *fieldFlag) IsBoolFlag() bool {
+	return f.fv.Kind() == reflect.Bool
+}
+
+// default value string of the flag. use parsed value or format the field value.
+func (f *fieldFlag) defaultValue() string {
+	if str, ok := f.p.smap[f.key]; ok {
+		return str
+	}
+
+	e := NewEncoder()
+	e.hooks = EncodeHooks("")
+	rv, str, ok := e.resolve(f.fv)
+	if ok {
+		return str
+	}
+
+	switch rv.Kind() {
+	case reflect.Invalid:
+		return ""
+	case reflect.Slice, reflect.Array:
+		ss, _ := e.commaElems(rv)
+		return strings.Join(ss, ",")
+	}
+	return reflects.String(rv)
+}
+
+// commentText remove comment chars and join multi lines. eg: "# some comment" -> "some comment"
+func commentText(s string) string {
+	if s == "" {
+		return ""
+	}
+
+	lines := strings.Split(s, "\n")
+	texts := make([]string, 0, len(lines))
+	for _, line := range lines {
+		line = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(line), "*/"))
+		for _, mark := range []string{"//", "/*", "#", "!", "*"} {
+			if strings.HasPrefix(line, mark) {
+				line = strings.TrimSpace(line[len(mark):])
+				break
+			}
+		}
+
+		if line != "" {
+			texts = append(texts, line)
+		}
+	}
+	return strings.Join(texts, " ")
+}
@@ -0,0 +1,123 @@
+package properties_test
+
+import (
+	"bytes"
+	"flag"
+	"testing"
+	"time"
+
+	"github.com/gookit/goutil/testutil/assert"
+	"github.com/gookit/properties"
+)
+
+func TestParser_OverlayArgs(t *testing.T) {
+	p := properties.NewParser()
+	assert.NoErr(t, p.Parse("name = demo\nserver.port = 8080"))
+
+	args := []string{"--server.port=9090", "-Dserver.host=127.0.0.1", "-v", "--debug", "run", "--", "--name=other"}
+	remain, err := p.OverlayArgs(args)
+	assert.NoErr(t, err)
+	assert.Eq(t, []string{"-v", "--debug", "run", "--name=other"}, remain)
+	assert.Eq(t, "demo", p.Str("name"))
+	assert.Eq(t, "9090", p.Str("server.port"))
+	assert.Eq(t, "127.0.0.1", p.Str("server.host"))
+
+	_, err = p.OverlayArgs([]string{"-Dinvalid"})
+	assert.ErrMsg(t, err, "invalid property arg: -Dinvalid")
+	_, err = p.OverlayArgs([]string{"--=val"})
+	assert.Err(t, err)
+}
+
+func TestParser_BindFlags(t *testing.T) {
+	type Server struct {
+		Host    string
+		Port    int
+		Timeout time.Duration
+	}
+
+	type Config struct {
+		Name   string
+		Debug  bool
+		Tags   []string
+		Server Server
+		Extra  map[string]string
+	}
+
+	text := `
+# app name
+name = demo
+debug = false
+tags = a,b
+# server port
+server.port = 8080
+server.timeout = 3s
+`
+	p := properties.NewParser()
+	assert.NoErr(t, p.Parse(text))
+
+	cfg := &Config{}
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	assert.NoErr(t, p.BindFlags(fs, cfg))
+	assert.Eq(t, "demo", cfg.Name)
+	assert.Eq(t, 8080, cfg.Server.Port)
+
+	fg := fs.Lookup("server.port")
+	assert.NotNil(t, fg)
+	assert.Eq(t, "8080", fg.DefValue)
+	assert.Eq(t, "server port", fg.Usage)
+	assert.Eq(t, "app name", fs.Lookup("name").Usage)
+	assert.NotNil(t, fs.Lookup("server.host"))
+	assert.Nil(t, fs.Lookup("server.Host"))
+	assert.Nil(t, fs.Lookup("extra"))
+
+	err := fs.Parse([]string{"--server.port=9090", "-debug", "--tags=c,d,e", "-server.timeout", "5s", "run"})
+	assert.NoErr(t, err)
+	assert.Eq(t, []string{"run"}, fs.Args())
+	assert.Eq(t, 9090, cfg.Server.Port)
+	assert.True(t, cfg.Debug)
+	assert.Eq(t, []string{"c", "d", "e"}, cfg.Tags)
+	assert.Eq(t, 5*time.Second, cfg.Server.Timeout)
+	// overlay to parser
+	assert.Eq(t, "9090", p.Str("server.port"))
+	assert.Eq(t, "true", p.Str("debug"))
+
+	// invalid value
+	fs.SetOutput(&bytes.Buffer{})
+	assert.Err(t, fs.Parse([]string{"--server.port=abc"}))
+
+	assert.Err(t, p.BindFlags(fs, Config{}))
+}
+
+func TestParser_BindFlags_keys(t *testing.T) {
+	type Server struct {
+		Key     string
+		MaxWait int
+	}
+	type Config struct {
+		Server Server
+	}
+
+	// the case folding changes the byte length. eg: "\u212A"(Kelvin sign) -> "k"
+	p, err := properties.Parse("Server.\u212Aey = v\nServer.max-wait = 3\n", properties.RelaxedBinding)
+	assert.NoErr(t, err)
+
+	cfg := &Config{}
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	assert.NoErr(t, p.BindFlags(fs, cfg))
+	assert.Eq(t, "v", cfg.Server.Key)
+	assert.Eq(t, 3, cfg.Server.MaxWait)
+	assert.NotNil(t, fs.Lookup("Server.\u212Aey"))
+	assert.NotNil(t, fs.Lookup("Server.max-wait"))
+
+	assert.NoErr(t, fs.Parse([]string{"--Server.max-wait=5"}))
+	assert.Eq(t, 5, cfg.Server.MaxWait)
+
+	// duplicate flag name
+	type Dup struct {
+		Name  string
+		Alias string `properties:"name"`
+	}
+	fs = flag.NewFlagSet("test", flag.ContinueOnError)
+	err = p.BindFlags(fs, &Dup{})
+	assert.ErrMsg(t, err, `flag "name" is already defined`)
+}
@@ -0,0 +1,228 @@
+package properties
+
+import (
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// FormatOptions for format the properties contents
+type FormatOptions struct {
+	// Compact use separator "=" instead of " = ". default: false
+	Compact bool
+	// Align align the separators and values within a block. default: false
+	//
+	// A block is the consecutive key-value lines with their leading comments, delimited by blank lines.
+	Align bool
+	// SortKeys sort the keys within a block, the leading comments will be moved with the key. default: false
+	//
+	// NOTE: the comments followed by a blank line are not belong to a key, will keep in place.
+	SortKeys bool
+	// ContinuationIndent for the continuation lines of the value ends with "\". default: 4 spaces
+	ContinuationIndent string
+	// ParseOptions for parse the contents. eg: ParseInlineSlice
+	ParseOptions []OpFunc
+}
+
+// Format the properties contents to the canonical style, will keep the comments and blank-line grouping.
+//
+//   - normalize the separator to " = " or "=", align values and sort keys within a block on enabled.
+//   - normalize escapes of the double-quoted value and the indentation of continuation lines.
+//   - trim the comment lines, collapse repeated blank lines.
+//
+// opts can be nil, will use the default options.
+func Format(src []byte, opts *FormatOptions) ([]byte, error) {
+	if opts == nil {
+		opts = &FormatOptions{}
+	}
+	if opts.ContinuationIndent == "" {
+		opts.ContinuationIndent = "    "
+	}
+
+	p, lines, err := parseLines(src, opts.ParseOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	// entries of all key-value lines, includes the overridden entries.
+	starts := make(map[int]*Entry, len(p.entries))
+	for _, e := range p.overridden {
+		starts[e.Line] = e
+	}
+	for _, e := range p.entries {
+		if e.Line > 0 {
+			starts[e.Line] = e
+		}
+	}
+
+	f := &formatter{opts: opts, lines: lines}
+	for ln := 1; ln <= len(lines); ln++ {
+		line := strings.TrimSpace(lines[ln-1])
+
+		if e, ok := starts[ln]; ok {
+			fe := f.entryLines(e)
+			// the leading comments belong to the key
+			fe.comments, f.comments = f.comments, nil
+			f.block = append(f.block, fe)
+			ln = e.lastLine()
+			continue
+		}
+
+		if line == "" {
+			f.flushBlock()
+			f.blank = len(f.out) > 0
+			continue
+		}
+
+		// multi line comments. eg: "/* ... */"
+		if strings.HasPrefix(line, "/*") && !strings.HasSuffix(line, MultiLineCmtEnd) {
+			f.comments = append(f.comments, line)
+			for ln++; ln <= len(lines); ln++ {
+				f.comments = append(f.comments, strings.TrimRight(lines[ln-1], " \t"))
+				if strings.HasSuffix(strings.TrimSpace(lines[ln-1]), MultiLineCmtEnd) {
+					break
+				}
+			}
+			continue
+		}
+		f.comments = append(f.comments, line)
+	}
+	f.flushBlock()
+
+	if len(f.out) == 0 {
+		return []byte{}, nil
+	}
+	return []byte(strings.Join(f.out, "\n") + "\n"), nil
+}
+
+type formatter struct {
+	opts  *FormatOptions
+	lines []string
+	out   []string
+	// need write a blank line before next line
+	blank bool
+	// current block of key-value lines
+	block []*fmtEntry
+	// comment lines before the next key
+	comments []string
+}
+
+// fmtEntry formatted lines of an entry, the separator will be added on flush.
+type fmtEntry struct {
+	key, value string
+	// leading comment lines of the key
+	comments []string
+	// more lines of the multi line value
+	more []string
+}
+
+func (f *formatter) writeLine(line string) {
+	if f.blank {
+		f.out = append(f.out, "")
+		f.blank = false
+	}
+	f.out = append(f.out, line)
+}
+
+func (f *formatter) entryLines(e *Entry) *fmtEntry {
+	first := f.lines[e.Line-1]
+	fe := &fmtEntry{key: e.Key}
+	if pos := strings.IndexByte(first, '='); pos > -1 {
+		fe.value = strings.TrimSpace(first[pos+1:])
+	}
+
+	val := fe.value
+	end := e.lastLine()
+	if end == e.Line {
+		// normalize escapes of the double-quoted value
+		if e.Quote == QuoteDouble && len(val) > 1 && val[0] == '"' && val[len(val)-1] == '"' {
+			fe.value = `"` + normalizeEscapes(val[1:len(val)-1]) + `"`
+		}
+		return fe
+	}
+
+	for ln := e.Line + 1; ln <= end; ln++ {
+		line := f.lines[ln-1]
+		if strings.HasSuffix(val, MultiLineValMarkQ) {
+			// continuation line, the indentation is not part of the value
+			line = f.opts.ContinuationIndent + strings.TrimSpace(line)
+		}
+		fe.more = append(fe.more, line)
+	}
+	return fe
+}
+
+// flush the current block, and the comments that are not belong to a key.
+func (f *formatter) flushBlock() {
+	defer func() {
+		for _, line := range f.comments {
+			f.writeLine(line)
+		}
+		f.comments = nil
+	}()
+
+	if len(f.block) == 0 {
+		return
+	}
+
+	if f.opts.SortKeys {
+		sort.SliceStable(f.block, func(i, j int) bool {
+			return f.block[i].key < f.block[j].key
+		})
+	}
+
+	var width int
+	if f.opts.Align {
+		for _, fe := range f.block {
+			if n := utf8.RuneCountInString(fe.key); n > width {
+				width = n
+			}
+		}
+	}
+
+	sep := " = "
+	if f.opts.Compact {
+		sep = "="
+	}
+
+	for _, fe := range f.block {
+		pad := width - utf8.RuneCountInString(fe.key)
+		if pad < 0 {
+			pad = 0
+		}
+
+		for _, cmt := range fe.comments {
+			f.writeLine(cmt)
+		}
+
+		line := fe.key + strings.Repeat(" ", pad) + sep + fe.value
+		f.writeLine(strings.TrimRight(line, " "))
+		f.out = append(f.out, fe.more...)
+	}
+	f.block = f.block[:0]
+}
+
+// normalizeEscapes of the double-quoted value, remove the unnecessary escape of single quote,
+// and escape the raw control chars. other escape sequences are kept as they are. eg: \u00e9, \x
+func normalizeEscapes(s string) string {
+	var sb strings.Builder
+	sb.Grow(len(s))
+
+	ln := len(s)
+	for i := 0; i < ln; i++ {
+		c := s[i]
+		switch {
+		case c == '\\' && i < ln-1:
+			i++
+			if s[i] != '\'' {
+				sb.WriteByte('\\')
+			}
+			sb.WriteByte(s[i])
+		case c == '\n', c == '\t', c == '\r':
+			sb.WriteString(escapeValue(s[i : i+1]))
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,137 @@
+package properties_test
+
+import (
+	"testing"
+
+	"github.com/gookit/goutil/testutil/assert"
+	"github.com/gookit/properties"
+)
+
+var fmtSrc = `
+
+  # app settings
+app.name=myapp
+app.desc  =   "a \'quoted\' value\x"
+app.uni = "caf\u00e9\t\"ok\""
+app.multi = line1 \
+        line2 \
+  line3
+
+
+
+/* multi line
+   comments */
+db.port =3306
+db.host= localhost
+db.empty =
+db.block = '''raw
+  kept   as is
+'''
+`
+
+func TestFormat(t *testing.T) {
+	bs, err := properties.Format([]byte(fmtSrc), nil)
+	assert.NoErr(t, err)
+	assert.Eq(t, `# app settings
+app.name = myapp
+app.desc = "a 'quoted' value\x"
+app.uni = "caf\u00e9\t\"ok\""
+app.multi = line1 \
+    line2 \
+    line3
+
+/* multi line
+   comments */
+db.port = 3306
+db.host = localhost
+db.empty =
+db.block = '''raw
+  kept   as is
+'''
+`, string(bs))
+
+	// the value is not changed
+	p1 := properties.NewParser()
+	assert.NoErr(t, p1.ParseBytes([]byte(fmtSrc)))
+	p2 := properties.NewParser()
+	assert.NoErr(t, p2.ParseBytes(bs))
+	assert.Eq(t, p1.SMap(), p2.SMap())
+
+	// format again, should be same
+	bs2, err := properties.Format(bs, nil)
+	assert.NoErr(t, err)
+	assert.Eq(t, string(bs), string(bs2))
+}
+
+func TestFormat_options(t *testing.T) {
+	src := `# section 1
+
+name = inhere
+age = 23
+city.name = chengdu
+
+# section 2
+
+# doc for b
+b.key = b
+/* doc for a
+   long key */
+a.long-key = a
+`
+
+	bs, err := properties.Format([]byte(src), &properties.FormatOptions{
+		Align:    true,
+		SortKeys: true,
+	})
+	assert.NoErr(t, err)
+	assert.Eq(t, `# section 1
+
+age       = 23
+city.name = chengdu
+name      = inhere
+
+# section 2
+
+/* doc for a
+   long key */
+a.long-key = a
+# doc for b
+b.key      = b
+`, string(bs))
+
+	// the comments are moved with the keys
+	p := properties.NewParser()
+	assert.NoErr(t, p.ParseBytes(bs))
+	assert.Eq(t, "# doc for b", p.Comments()["b.key"])
+
+	bs, err = properties.Format([]byte("a = 1\nb = x \\\n  y\n"), &properties.FormatOptions{
+		Compact:            true,
+		ContinuationIndent: "\t",
+	})
+	assert.NoErr(t, err)
+	assert.Eq(t, "a=1\nb=x \\\n\ty\n", string(bs))
+
+	// inline slice
+	src = "ids = [\n  1,\n  2\n]\nname = inhere\n"
+	_, err = properties.Format([]byte(src), nil)
+	assert.Err(t, err)
+
+	bs, err = properties.Format([]byte(src), &properties.FormatOptions{
+		ParseOptions: []properties.OpFunc{properties.ParseInlineSlice},
+	})
+	assert.NoErr(t, err)
+	assert.Eq(t, src, string(bs))
+}
+
+func TestFormat_duplicate(t *testing.T) {
+	bs, err := properties.Format([]byte("b=2\na=1\nb=3\n"), &properties.FormatOptions{SortKeys: true})
+	assert.NoErr(t, err)
+	assert.Eq(t, "a = 1\nb = 2\nb = 3\n", string(bs))
+
+	bs, err = properties.Format([]byte("\n\n"), nil)
+	assert.NoErr(t, err)
+	assert.Eq(t, "", string(bs))
+
+	_, err = properties.Format([]byte("name = '''abc\n"), nil)
+	assert.Err(t, err)
+}
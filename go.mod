@@ -3,8 +3,10 @@ module github.com/gookit/properties
 go 1.19
 
 require (
+	github.com/BurntSushi/toml v1.5.0
 	github.com/go-viper/mapstructure/v2 v2.5.0
 	github.com/gookit/goutil v0.8.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
@@ -0,0 +1,345 @@
+package properties
+
+import (
+	"fmt"
+	"math"
+	"net"
+	"net/netip"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-viper/mapstructure/v2"
+)
+
+// DefaultTimeLayouts for parse time string to time.Time on binding struct.
+var DefaultTimeLayouts = []string{
+	time.RFC3339Nano,
+	time.RFC3339,
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+var (
+	durationType = reflect.TypeOf(time.Duration(0))
+	timeType     = reflect.TypeOf(time.Time{})
+	urlType      = reflect.TypeOf(url.URL{})
+	ipType       = reflect.TypeOf(net.IP{})
+	ipNetType    = reflect.TypeOf(net.IPNet{})
+	ipAddrType   = reflect.TypeOf(netip.Addr{})
+	ipPrefixType = reflect.TypeOf(netip.Prefix{})
+	regexpType   = reflect.TypeOf(regexp.Regexp{})
+)
+
+// ValDecodeHookFunc returns a mapstructure.DecodeHookFunc that contains all
+// builtin value hooks. see DecodeHooks()
+func ValDecodeHookFunc() mapstructure.DecodeHookFunc {
+	return mapstructure.ComposeDecodeHookFunc(DecodeHooks()...)
+}
+
+// DecodeHooks returns all builtin decode hooks, the order is:
+//
+//	duration, size, time, url, ip, regexp, slice, text unmarshaler
+func DecodeHooks() []mapstructure.DecodeHookFunc {
+	return builtinHooks(true)
+}
+
+// builtinHooks by the order of DecodeHooks(), the duration and time hooks are skipped on parseTime=false.
+func builtinHooks(parseTime bool, timeLayouts ...string) []mapstructure.DecodeHookFunc {
+	hooks := make([]mapstructure.DecodeHookFunc, 0, 8)
+	if parseTime {
+		hooks = append(hooks, DurationHookFunc())
+	}
+	hooks = append(hooks, SizeHookFunc())
+	if parseTime {
+		hooks = append(hooks, TimeHookFunc(timeLayouts...))
+	}
+
+	return append(hooks,
+		URLHookFunc(),
+		IPHookFunc(),
+		RegexpHookFunc(),
+		SliceHookFunc(","),
+		TextUnmarshalerHookFunc(),
+	)
+}
+
+// DurationHookFunc returns a mapstructure.DecodeHookFunc that parse duration string.
+//
+// eg: "3s", "-1m", "0s", "1h30m" -> time.Duration
+//
+// If target is a int64 field(not time.Duration), the string must end with a unit.
+func DurationHookFunc() mapstructure.DecodeHookFunc {
+	return func(f reflect.Type, t reflect.Type, data any) (any, error) {
+		if f.Kind() != reflect.String {
+			return data, nil
+		}
+
+		str := strings.TrimSpace(data.(string))
+		if t == durationType {
+			if str == "" {
+				return time.Duration(0), nil
+			}
+			// plain number, as nanoseconds
+			if n, err := strconv.ParseInt(str, 10, 64); err == nil {
+				return time.Duration(n), nil
+			}
+			return time.ParseDuration(str)
+		}
+
+		// compatible: int64 field with duration string. eg: 10s
+		if t.Kind() == reflect.Int64 && isDurationStr(str) {
+			if dur, err := time.ParseDuration(str); err == nil {
+				return dur, nil
+			}
+		}
+		return data, nil
+	}
+}
+
+// check string looks like a duration. eg: 10s, -3m
+func isDurationStr(str string) bool {
+	ln := len(str)
+	if ln < 2 {
+		return false
+	}
+
+	first := str[0]
+	if first == '-' || first == '+' {
+		if ln < 3 {
+			return false
+		}
+		first = str[1]
+	}
+
+	last := str[ln-1]
+	return first >= '0' && first <= '9' && last >= 'a' && last <= 'z'
+}
+
+// eg: 10MB, 512KiB, 1.5 GB, 20B
+var sizeRegex = regexp.MustCompile(`^(\d+(?:\.\d+)?)\s*([KMGTP]I?)?B$`)
+
+var sizeUnits = map[string]float64{
+	"":  1,
+	"K": 1 << 10,
+	"M": 1 << 20,
+	"G": 1 << 30,
+	"T": 1 << 40,
+	"P": 1 << 50,
+}
+
+// ParseByteSize parse byte size string to bytes number.
+//
+// Unit is case-insensitive, KB and KiB are both 1024 bytes. eg:
+//
+//	"20B" -> 20
+//	"10MB" -> 10485760
+//	"512KiB" -> 524288
+func ParseByteSize(str string) (uint64, error) {
+	ss := sizeRegex.FindStringSubmatch(strings.ToUpper(strings.TrimSpace(str)))
+	if len(ss) == 0 {
+		return 0, fmt.Errorf("invalid byte size string %q", str)
+	}
+
+	num, err := strconv.ParseFloat(ss[1], 64)
+	if err != nil {
+		return 0, err
+	}
+
+	size := num * sizeUnits[strings.TrimSuffix(ss[2], "I")]
+	if size >= math.MaxUint64 {
+		return 0, fmt.Errorf("byte size %q is out of range", str)
+	}
+	return uint64(size), nil
+}
+
+// SizeHookFunc returns a mapstructure.DecodeHookFunc that parse byte size string
+// to int and uint fields. eg: "10MB", "512KiB"
+func SizeHookFunc() mapstructure.DecodeHookFunc {
+	return func(f reflect.Type, t reflect.Type, data any) (any, error) {
+		if f.Kind() != reflect.String || t == durationType {
+			return data, nil
+		}
+
+		switch t.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		default:
+			return data, nil
+		}
+
+		str := data.(string)
+		if !sizeRegex.MatchString(strings.ToUpper(strings.TrimSpace(str))) {
+			return data, nil
+		}
+
+		size, err := ParseByteSize(str)
+		if err != nil {
+			return nil, err
+		}
+
+		// check the size is in range of the target type. eg: uint8 max is 255
+		rv := reflect.New(t).Elem()
+		switch t.Kind() {
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			if rv.OverflowUint(size) {
+				return nil, fmt.Errorf("byte size %q overflows %s", str, t)
+			}
+			rv.SetUint(size)
+		default:
+			if size > math.MaxInt64 || rv.OverflowInt(int64(size)) {
+				return nil, fmt.Errorf("byte size %q overflows %s", str, t)
+			}
+			rv.SetInt(int64(size))
+		}
+		return rv.Interface(), nil
+	}
+}
+
+// TimeHookFunc returns a mapstructure.DecodeHookFunc that parse string to time.Time.
+//
+// Will try parse by each layout, if not set layouts, will use DefaultTimeLayouts.
+func TimeHookFunc(layouts ...string) mapstructure.DecodeHookFunc {
+	return func(f reflect.Type, t reflect.Type, data any) (any, error) {
+		if f.Kind() != reflect.String || t != timeType {
+			return data, nil
+		}
+
+		str := strings.TrimSpace(data.(string))
+		if str == "" {
+			return time.Time{}, nil
+		}
+
+		tryLayouts := layouts
+		if len(tryLayouts) == 0 {
+			tryLayouts = DefaultTimeLayouts
+		}
+
+		for _, layout := range tryLayouts {
+			if tt, err := time.Parse(layout, str); err == nil {
+				return tt, nil
+			}
+		}
+		return nil, fmt.Errorf("cannot parse %q as time.Time", str)
+	}
+}
+
+// URLHookFunc returns a mapstructure.DecodeHookFunc that parse string to url.URL or *url.URL
+func URLHookFunc() mapstructure.DecodeHookFunc {
+	return func(f reflect.Type, t reflect.Type, data any) (any, error) {
+		if f.Kind() != reflect.String {
+			return data, nil
+		}
+
+		isPtr := t.Kind() == reflect.Ptr
+		if isPtr {
+			t = t.Elem()
+		}
+		if t != urlType {
+			return data, nil
+		}
+
+		u, err := url.Parse(strings.TrimSpace(data.(string)))
+		if err != nil || isPtr {
+			return u, err
+		}
+		return *u, nil
+	}
+}
+
+// IPHookFunc returns a mapstructure.DecodeHookFunc that parse string to
+// net.IP, net.IPNet, netip.Addr or netip.Prefix
+func IPHookFunc() mapstructure.DecodeHookFunc {
+	return func(f reflect.Type, t reflect.Type, data any) (any, error) {
+		if f.Kind() != reflect.String {
+			return data, nil
+		}
+
+		str := strings.TrimSpace(data.(string))
+		// empty string as the zero value
+		if str == "" {
+			switch t {
+			case ipType, ipNetType, reflect.PointerTo(ipNetType), ipAddrType, ipPrefixType:
+				return reflect.Zero(t).Interface(), nil
+			}
+			return data, nil
+		}
+
+		switch t {
+		case ipType:
+			ip := net.ParseIP(str)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid IP address %q", str)
+			}
+			return ip, nil
+		case ipNetType:
+			_, ipNet, err := net.ParseCIDR(str)
+			if err != nil {
+				return nil, err
+			}
+			return *ipNet, nil
+		case reflect.PointerTo(ipNetType):
+			_, ipNet, err := net.ParseCIDR(str)
+			return ipNet, err
+		case ipAddrType:
+			return netip.ParseAddr(str)
+		case ipPrefixType:
+			return netip.ParsePrefix(str)
+		}
+		return data, nil
+	}
+}
+
+// RegexpHookFunc returns a mapstructure.DecodeHookFunc that compile string to *regexp.Regexp
+func RegexpHookFunc() mapstructure.DecodeHookFunc {
+	return func(f reflect.Type, t reflect.Type, data any) (any, error) {
+		if f.Kind() != reflect.String {
+			return data, nil
+		}
+
+		if t.Kind() == reflect.Ptr && t.Elem() == regexpType {
+			return regexp.Compile(data.(string))
+		}
+		return data, nil
+	}
+}
+
+// SliceHookFunc returns a mapstructure.DecodeHookFunc that split string to slice by sep.
+// Like Spring, will trim space for each element and remove empty elements.
+//
+// eg: "a, b,c" -> []string{"a", "b", "c"}
+func SliceHookFunc(sep string) mapstructure.DecodeHookFunc {
+	return func(f reflect.Type, t reflect.Type, data any) (any, error) {
+		if f.Kind() != reflect.String || t.Kind() != reflect.Slice {
+			return data, nil
+		}
+		// skip []byte and net.IP
+		if t.Elem().Kind() == reflect.Uint8 {
+			return data, nil
+		}
+
+		str := strings.TrimSpace(data.(string))
+		if ln := len(str); ln > 1 && str[0] == '[' && str[ln-1] == ']' {
+			str = str[1 : ln-1]
+		}
+
+		ss := make([]string, 0, 4)
+		for _, s := range strings.Split(str, sep) {
+			if s = strings.TrimSpace(s); s != "" {
+				ss = append(ss, s)
+			}
+		}
+		return ss, nil
+	}
+}
+
+// TextUnmarshalerHookFunc returns a mapstructure.DecodeHookFunc that applies
+// strings to the UnmarshalText func, when the target type implements the encoding.TextUnmarshaler
+func TextUnmarshalerHookFunc() mapstructure.DecodeHookFunc {
+	return mapstructure.TextUnmarshallerHookFunc()
+}
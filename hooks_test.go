@@ -0,0 +1,174 @@
+package properties_test
+
+import (
+	"net"
+	"net/netip"
+	"net/url"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/gookit/goutil/testutil/assert"
+	"github.com/gookit/properties"
+)
+
+func TestValDecodeHookFunc_duration(t *testing.T) {
+	text := `
+dur0 = 0s
+dur1 = 9m
+dur2 = -3s
+dur3 = 1h30m
+int64 = 10s
+`
+
+	type MyConf struct {
+		Dur0  time.Duration `properties:"dur0"`
+		Dur1  time.Duration `properties:"dur1"`
+		Dur2  time.Duration `properties:"dur2"`
+		Dur3  time.Duration `properties:"dur3"`
+		Int64 int64         `properties:"int64"`
+	}
+
+	cfg := &MyConf{}
+	err := properties.Unmarshal([]byte(text), cfg)
+	assert.NoErr(t, err)
+	assert.Eq(t, time.Duration(0), cfg.Dur0)
+	assert.Eq(t, 9*time.Minute, cfg.Dur1)
+	assert.Eq(t, -3*time.Second, cfg.Dur2)
+	assert.Eq(t, 90*time.Minute, cfg.Dur3)
+	assert.Eq(t, int64(10*time.Second), cfg.Int64)
+}
+
+func TestParseByteSize(t *testing.T) {
+	tests := map[string]uint64{
+		"20B":    20,
+		"1kb":    1024,
+		"10MB":   10 << 20,
+		"512KiB": 512 << 10,
+		"1.5 GB": 3 << 29,
+	}
+
+	for str, want := range tests {
+		size, err := properties.ParseByteSize(str)
+		assert.NoErr(t, err)
+		assert.Eq(t, want, size, str)
+	}
+
+	_, err := properties.ParseByteSize("10MX")
+	assert.ErrSubMsg(t, err, "invalid byte size string")
+	_, err = properties.ParseByteSize("99999999PB")
+	assert.ErrSubMsg(t, err, "is out of range")
+}
+
+func TestSizeHookFunc_overflow(t *testing.T) {
+	type Size int16
+	cfg := &struct {
+		U8  uint8
+		Sz  int64
+		Typ Size
+	}{}
+
+	err := properties.Unmarshal([]byte("u8 = 255B\nsz = 1PB\ntyp = 1KB"), cfg)
+	assert.NoErr(t, err)
+	assert.Eq(t, uint8(255), cfg.U8)
+	assert.Eq(t, int64(1<<50), cfg.Sz)
+	assert.Eq(t, Size(1024), cfg.Typ)
+
+	err = properties.Unmarshal([]byte("u8 = 1KB"), cfg)
+	assert.ErrSubMsg(t, err, `byte size "1KB" overflows uint8`)
+	err = properties.Unmarshal([]byte("sz = 9999PB"), cfg)
+	assert.ErrSubMsg(t, err, `byte size "9999PB" overflows int64`)
+	err = properties.Unmarshal([]byte("sz = 99999999PB"), cfg)
+	assert.ErrSubMsg(t, err, "is out of range")
+}
+
+func TestValDecodeHookFunc_types(t *testing.T) {
+	text := `
+max-size = 10MB
+buf-size = 512KiB
+port = 8080
+start-at = 2024-01-02 15:04:05
+day = 2024-01-02
+home = https://github.com/gookit/properties
+api = http://127.0.0.1:8080/api
+ip = 127.0.0.1
+addr = ::1
+cidr = 10.0.0.0/8
+prefix = 192.168.0.0/16
+pattern = ^app-\d+$
+hosts = a.com, b.com,c.com
+ports = 80,443
+`
+
+	type MyConf struct {
+		MaxSize uint64         `properties:"max-size"`
+		BufSize int            `properties:"buf-size"`
+		Port    int            `properties:"port"`
+		StartAt time.Time      `properties:"start-at"`
+		Day     time.Time      `properties:"day"`
+		Home    url.URL        `properties:"home"`
+		API     *url.URL       `properties:"api"`
+		IP      net.IP         `properties:"ip"`
+		Addr    netip.Addr     `properties:"addr"`
+		CIDR    *net.IPNet     `properties:"cidr"`
+		Prefix  netip.Prefix   `properties:"prefix"`
+		Pattern *regexp.Regexp `properties:"pattern"`
+		Hosts   []string       `properties:"hosts"`
+		Ports   []int          `properties:"ports"`
+	}
+
+	cfg := &MyConf{}
+	err := properties.Unmarshal([]byte(text), cfg)
+	assert.NoErr(t, err)
+	assert.Eq(t, uint64(10<<20), cfg.MaxSize)
+	assert.Eq(t, 512<<10, cfg.BufSize)
+	assert.Eq(t, 8080, cfg.Port)
+	assert.Eq(t, "2024-01-02 15:04:05", cfg.StartAt.Format("2006-01-02 15:04:05"))
+	assert.Eq(t, 2, cfg.Day.Day())
+	assert.Eq(t, "github.com", cfg.Home.Host)
+	assert.Eq(t, "/api", cfg.API.Path)
+	assert.Eq(t, "127.0.0.1", cfg.IP.String())
+	assert.True(t, cfg.Addr.Is6())
+	assert.Eq(t, "10.0.0.0/8", cfg.CIDR.String())
+	assert.Eq(t, 16, cfg.Prefix.Bits())
+	assert.True(t, cfg.Pattern.MatchString("app-12"))
+	assert.Eq(t, []string{"a.com", "b.com", "c.com"}, cfg.Hosts)
+	assert.Eq(t, []int{80, 443}, cfg.Ports)
+}
+
+func TestIPHookFunc_empty(t *testing.T) {
+	type MyConf struct {
+		IP     net.IP       `properties:"ip"`
+		Addr   netip.Addr   `properties:"addr"`
+		Net    net.IPNet    `properties:"net"`
+		CIDR   *net.IPNet   `properties:"cidr"`
+		Prefix netip.Prefix `properties:"prefix"`
+	}
+
+	cfg := &MyConf{}
+	err := properties.Unmarshal([]byte("ip =\naddr =\nnet =\ncidr =\nprefix =\n"), cfg)
+	assert.NoErr(t, err)
+	assert.Nil(t, cfg.IP)
+	assert.False(t, cfg.Addr.IsValid())
+	assert.Nil(t, cfg.Net.IP)
+	assert.Nil(t, cfg.CIDR)
+	assert.False(t, cfg.Prefix.IsValid())
+}
+
+func TestTimeHookFunc_layouts(t *testing.T) {
+	type MyConf struct {
+		Day time.Time `properties:"day"`
+	}
+
+	p := properties.NewParser(func(opts *properties.Options) {
+		opts.MapStructConfig.DecodeHook = properties.TimeHookFunc("02/01/2006")
+	})
+	assert.NoErr(t, p.Parse("day = 25/12/2023"))
+
+	cfg := &MyConf{}
+	assert.NoErr(t, p.Decode(cfg))
+	assert.Eq(t, time.December, cfg.Day.Month())
+
+	assert.NoErr(t, p.Parse("day = 2023-12-25"))
+	assert.ErrSubMsg(t, p.Decode(cfg), "cannot parse")
+}
@@ -0,0 +1,117 @@
+package properties
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ToINI convert the parsed data to INI. the top-level map key will be as section.
+//
+// eg: "db.url=jdbc" -> "[db]\nurl = jdbc"
+func ToINI(p *Parser) ([]byte, error) {
+	keys := p.smap.Keys()
+	sort.Strings(keys)
+
+	var top []string
+	sections := make(map[string][]string)
+	var names []string
+	for _, key := range keys {
+		name, sub, ok := strings.Cut(key, ".")
+		if _, isMap := asMap(p.Data[name]); !ok || !isMap {
+			top = append(top, key)
+			continue
+		}
+
+		if _, exists := sections[name]; !exists {
+			names = append(names, name)
+		}
+		sections[name] = append(sections[name], sub)
+	}
+
+	var buf bytes.Buffer
+	for _, key := range top {
+		writeINILine(&buf, key, p.smap[key])
+	}
+
+	for _, name := range names {
+		if buf.Len() > 0 {
+			buf.WriteByte('\n')
+		}
+		buf.WriteString("[" + name + "]\n")
+		for _, sub := range sections[name] {
+			writeINILine(&buf, sub, p.smap[name+"."+sub])
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+func writeINILine(buf *bytes.Buffer, key, val string) {
+	if val != strings.TrimSpace(val) || strings.ContainsAny(val, ";#\"\n\r") {
+		val = quoteValue(val, QuoteDouble)
+	}
+	buf.WriteString(key + " = " + val + "\n")
+}
+
+// FromINI convert INI to properties Parser. the section name will be as key prefix.
+//
+// eg: "[db]\nurl = jdbc" -> "db.url=jdbc"
+func FromINI(bs []byte, optFns ...OpFunc) (*Parser, error) {
+	data := make(map[string]any)
+
+	var section string
+	var lineNo int
+	s := bufio.NewScanner(bytes.NewReader(bs))
+	for s.Scan() {
+		lineNo++
+		line := strings.TrimSpace(s.Text())
+		if line == "" || line[0] == ';' || line[0] == '#' {
+			continue
+		}
+
+		if line[0] == '[' {
+			if line[len(line)-1] != ']' {
+				return nil, fmt.Errorf("ini: line %d: invalid section %q", lineNo, line)
+			}
+			section = strings.TrimSpace(line[1 : len(line)-1])
+			continue
+		}
+
+		pos := strings.IndexAny(line, "=:")
+		if pos < 1 {
+			return nil, fmt.Errorf("ini: line %d: invalid line %q", lineNo, line)
+		}
+
+		key := strings.TrimSpace(line[:pos])
+		if section != "" {
+			key = section + "." + key
+		}
+		data[key] = iniValue(strings.TrimSpace(line[pos+1:]))
+	}
+
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	return FromMap(data, optFns...)
+}
+
+// parse the INI value, will remove quotes and inline comments.
+func iniValue(val string) string {
+	if ln := len(val); ln > 1 && (val[0] == '"' || val[0] == '\'') {
+		if end := strings.LastIndexByte(val, val[0]); end > 0 {
+			if val[0] == '"' {
+				return unescapeValue(val[1:end])
+			}
+			return val[1:end]
+		}
+	}
+
+	for _, mark := range []string{" ;", " #"} {
+		if pos := strings.Index(val, mark); pos > -1 {
+			val = val[:pos]
+		}
+	}
+	return strings.TrimSpace(val)
+}
@@ -0,0 +1,271 @@
+package properties
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gookit/goutil/envutil"
+	"github.com/gookit/goutil/maputil"
+)
+
+// source names for the non-file sources
+const (
+	SourceEnv  = "env"
+	SourceArgs = "args"
+)
+
+// Origin the provenance of an effective key value in the Layered config
+type Origin struct {
+	Key   string
+	Value string
+	// Source name. eg: file path, "env:APP_DB_URL", "args"
+	Source string
+	// Line number in the source, is 0 on the source is not text.
+	Line int
+	// Overrides the overridden origins by this, ordered by load order.
+	Overrides []*Origin
+	// the value is decrypted from ENC(...) value
+	encrypted bool
+}
+
+// String location of the origin. eg: app.properties:3
+func (o *Origin) String() string {
+	if o.Line > 0 {
+		return o.Source + ":" + strconv.Itoa(o.Line)
+	}
+	return o.Source
+}
+
+// Layered config, merge multi sources by load order and record the origin of each key.
+//
+// The later loaded source will override the earlier. eg:
+//
+//	l := properties.NewLayered()
+//	l.LoadText("defaults", defaults)
+//	l.LoadFile("app.properties")
+//	l.LoadFile("app-prod.properties")
+//	l.LoadEnv("APP_")
+//	l.LoadArgs(os.Args[1:])
+//
+//	o, ok := l.Origin("db.url") // where did db.url come from?
+type Layered struct {
+	// effective config data
+	p    *Parser
+	opts []OpFunc
+	// origins of the effective keys
+	origins map[string]*Origin
+	// SecretKeys for mask the value on dump. default: DefaultSecretKeys
+	SecretKeys []string
+}
+
+// NewLayered instance. optFns will be used for parse each source.
+func NewLayered(optFns ...OpFunc) *Layered {
+	return &Layered{
+		p:       NewParser(optFns...),
+		opts:    optFns,
+		origins: make(map[string]*Origin),
+	}
+}
+
+// LoadText parse the text contents as a source layer.
+func (l *Layered) LoadText(source, text string) error {
+	tmp := NewParser(l.opts...)
+	// env overlay should be load by LoadEnv
+	tmp.opts.EnvPrefix = ""
+	// allow refer the var from earlier sources
+	for k, v := range l.p.smap {
+		tmp.smap[k] = v
+	}
+
+	if err := tmp.Parse(text); err != nil {
+		return err
+	}
+
+	entries := make([]*Entry, 0, len(tmp.entries))
+	for _, e := range tmp.entries {
+		entries = append(entries, e)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Line < entries[j].Line
+	})
+
+	for _, e := range entries {
+		val, ok := maputil.GetByPath(pathOfKey(e.Key), tmp.Data)
+		if !ok {
+			continue
+		}
+
+		ne := *e
+		l.p.entries[e.Key] = &ne
+		l.p.smap[e.Key] = e.Value
+		if e.Comment != "" {
+			l.p.comments[e.Key] = e.Comment
+		}
+		l.p.collect(e.Key, val)
+		if l.p.err != nil {
+			return l.p.err
+		}
+		l.record(e.Key, e.Value, source, e.Line, e.Encrypted())
+	}
+	return nil
+}
+
+// LoadFile parse the file contents as a source layer, the source name is the file path.
+func (l *Layered) LoadFile(filePath string) error {
+	bs, err := os.ReadFile(filePath)
+	if err != nil {
+		return err
+	}
+	return l.LoadText(filePath, string(bs))
+}
+
+// LoadFileIfExist load the file on it's exists. eg: an optional profile file
+func (l *Layered) LoadFileIfExist(filePath string) error {
+	if _, err := os.Stat(filePath); err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	return l.LoadFile(filePath)
+}
+
+// LoadEnv overlay the env vars that start with the prefix. see Parser.OverlayEnv
+func (l *Layered) LoadEnv(prefix string) error {
+	return l.LoadEnvMap(prefix, envutil.Environ())
+}
+
+// LoadEnvMap overlay the env map that start with the prefix. see Parser.OverlayEnv
+func (l *Layered) LoadEnvMap(prefix string, envs map[string]string) error {
+	return l.overlay(l.p.envPairs(prefix, envs), true)
+}
+
+// LoadArgs overlay the command line args, returns the remaining args. see Parser.OverlayArgs
+func (l *Layered) LoadArgs(args []string) ([]string, error) {
+	pairs, remain, err := parseArgs(args)
+	if err != nil {
+		return nil, err
+	}
+	return remain, l.overlay(pairs, false)
+}
+
+func (l *Layered) overlay(pairs []kvPair, isEnv bool) error {
+	for _, kv := range pairs {
+		l.p.overlayValue(kv.key, kv.value)
+		if l.p.err != nil {
+			return l.p.err
+		}
+
+		if isEnv {
+			l.record(kv.key, kv.value, SourceEnv+":"+kv.src, 0, false)
+		} else {
+			l.record(kv.key, kv.value, SourceArgs, 0, false)
+		}
+	}
+	return nil
+}
+
+func (l *Layered) record(key, value, source string, line int, encrypted bool) {
+	o := &Origin{Key: key, Value: value, Source: source, Line: line, encrypted: encrypted}
+	if old, ok := l.origins[key]; ok {
+		o.Overrides = append(old.Overrides, &Origin{
+			Key:       key,
+			Value:     old.Value,
+			Source:    old.Source,
+			Line:      old.Line,
+			encrypted: old.encrypted,
+		})
+	}
+	l.origins[key] = o
+}
+
+// Parser get the effective config data
+func (l *Layered) Parser() *Parser {
+	return l.p
+}
+
+// Decode the effective config data to struct ptr
+func (l *Layered) Decode(ptr any) error {
+	return l.p.Decode(ptr)
+}
+
+// Origin get the origin of the effective key
+func (l *Layered) Origin(key string) (*Origin, bool) {
+	o, ok := l.origins[key]
+	return o, ok
+}
+
+// Origins of all effective keys
+func (l *Layered) Origins() map[string]*Origin {
+	return l.origins
+}
+
+// Dump the effective config with origins to the writer, the secret and decrypted values will be masked. eg:
+//
+//	db.password = ****** # from: env:APP_DB_PASSWORD, overrides: app.properties:3
+//	db.url = jdbc:prod # from: app-prod.properties:2, overrides: app.properties:2="jdbc:dev"
+func (l *Layered) Dump(w io.Writer) error {
+	keys := make([]string, 0, len(l.origins))
+	for key := range l.origins {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var buf bytes.Buffer
+	for _, key := range keys {
+		o := l.origins[key]
+		secret := IsSecretKey(key, l.SecretKeys...)
+
+		buf.WriteString(key)
+		buf.WriteString(" = ")
+		buf.WriteString(l.dumpValue(o.Value, secret || o.encrypted))
+		buf.WriteString(" # from: ")
+		buf.WriteString(o.String())
+
+		for i, ov := range o.Overrides {
+			if i == 0 {
+				buf.WriteString(", overrides: ")
+			} else {
+				buf.WriteString("; ")
+			}
+			buf.WriteString(ov.String())
+			if !secret && !ov.encrypted {
+				buf.WriteByte('=')
+				buf.WriteString(strconv.Quote(ov.Value))
+			}
+		}
+		buf.WriteByte('\n')
+	}
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// DumpString the effective config with origins. see Dump
+func (l *Layered) DumpString() string {
+	var sb strings.Builder
+	_ = l.Dump(&sb)
+	return sb.String()
+}
+
+func (l *Layered) dumpValue(val string, secret bool) string {
+	if secret {
+		return MaskValue(val)
+	}
+	if needQuote(val) {
+		return quoteValue(val, QuoteDouble)
+	}
+	return val
+}
+
+// pathOfKey convert key to data path. eg: "users[0].name" -> "users.0.name"
+func pathOfKey(key string) string {
+	if !strings.ContainsRune(key, '[') {
+		return key
+	}
+	return strings.NewReplacer("[", ".", "]", "").Replace(key)
+}
@@ -0,0 +1,88 @@
+package properties_test
+
+import (
+	"testing"
+
+	"github.com/gookit/goutil/testutil/assert"
+	"github.com/gookit/properties"
+)
+
+func TestLayered(t *testing.T) {
+	l := properties.NewLayered(properties.InferType)
+
+	assert.NoErr(t, l.LoadText("defaults", `
+name = demo
+db.url = jdbc:dev
+db.password = dev-pwd
+db.pool = 5
+`))
+	assert.NoErr(t, l.LoadText("app-prod.properties", `
+# prod db url
+db.url = jdbc:prod
+db.host = ${name}
+`))
+	assert.NoErr(t, l.LoadFileIfExist("testdata/not-exists.properties"))
+	assert.Err(t, l.LoadFile("testdata/not-exists.properties"))
+
+	assert.NoErr(t, l.LoadEnvMap("APP_", map[string]string{
+		"APP_DB_PASSWORD": "prod-pwd",
+		"APP_DB_POOL":     "10",
+	}))
+	remain, err := l.LoadArgs([]string{"--db.pool=20", "run"})
+	assert.NoErr(t, err)
+	assert.Eq(t, []string{"run"}, remain)
+
+	p := l.Parser()
+	assert.Eq(t, "demo", p.Str("name"))
+	assert.Eq(t, "jdbc:prod", p.Str("db.url"))
+	assert.Eq(t, "demo", p.Str("db.host"))
+	assert.Eq(t, int64(20), p.Get("db.pool"))
+	assert.Eq(t, "# prod db url", p.Comments()["db.url"])
+
+	o, ok := l.Origin("db.url")
+	assert.True(t, ok)
+	assert.Eq(t, "app-prod.properties", o.Source)
+	assert.Eq(t, 3, o.Line)
+	assert.Eq(t, "app-prod.properties:3", o.String())
+	assert.Len(t, o.Overrides, 1)
+	assert.Eq(t, "defaults", o.Overrides[0].Source)
+	assert.Eq(t, 3, o.Overrides[0].Line)
+	assert.Eq(t, "jdbc:dev", o.Overrides[0].Value)
+
+	o, ok = l.Origin("db.pool")
+	assert.True(t, ok)
+	assert.Eq(t, "args", o.Source)
+	assert.Len(t, o.Overrides, 2)
+	assert.Eq(t, "env:APP_DB_POOL", o.Overrides[1].String())
+
+	_, ok = l.Origin("not-exists")
+	assert.False(t, ok)
+	assert.Len(t, l.Origins(), 5)
+
+	type DB struct {
+		URL  string `properties:"url"`
+		Pool int
+	}
+	db := &DB{}
+	assert.NoErr(t, p.MapStruct("db", db))
+	assert.Eq(t, 20, db.Pool)
+
+	want := `db.host = demo # from: app-prod.properties:4
+db.password = ****** # from: env:APP_DB_PASSWORD, overrides: defaults:4
+db.pool = 20 # from: args, overrides: defaults:5="5"; env:APP_DB_POOL="10"
+db.url = jdbc:prod # from: app-prod.properties:3, overrides: defaults:3="jdbc:dev"
+name = demo # from: defaults:2
+`
+	assert.Eq(t, want, l.DumpString())
+}
+
+func TestIsSecretKey(t *testing.T) {
+	assert.True(t, properties.IsSecretKey("db.password"))
+	assert.True(t, properties.IsSecretKey("app.API_KEY"))
+	assert.True(t, properties.IsSecretKey("oauth.client-secret"))
+	assert.False(t, properties.IsSecretKey("db.url"))
+	assert.True(t, properties.IsSecretKey("db.url", "url"))
+
+	assert.Eq(t, "******", properties.MaskValue("abc"))
+	assert.Eq(t, "", properties.MaskValue(""))
+}
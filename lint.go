@@ -0,0 +1,340 @@
+package properties
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"github.com/gookit/goutil/strutil/textscan"
+)
+
+// lint rule names
+const (
+	// RuleSyntax invalid contents, can not be parsed. it is always enabled.
+	RuleSyntax = "syntax"
+	// RuleDuplicateKey the key is defined more than once.
+	RuleDuplicateKey = "duplicate-key"
+	// RuleUnresolvedRef the "${name}" reference can not be resolved.
+	RuleUnresolvedRef = "unresolved-ref"
+	// RuleTrailingSpace trailing whitespace in the value line.
+	RuleTrailingSpace = "trailing-space"
+	// RuleKeyNaming the key not match the naming pattern. see Linter.KeyPattern
+	RuleKeyNaming = "key-naming"
+	// RulePlainSecret secret value in plain text. see Linter.SecretKeys
+	RulePlainSecret = "plain-secret"
+	// RuleUnclosedBlock the multi line value is not closed, or looks like not closed.
+	RuleUnclosedBlock = "unclosed-block"
+	// RuleMixedSeparator the key-value separator style is different from others. eg: "k=v" and "k = v"
+	RuleMixedSeparator = "mixed-separator"
+	// RuleNonASCII non-ASCII chars without unicode escapes.
+	RuleNonASCII = "non-ascii"
+)
+
+// LintRules all builtin lint rules, can be disabled by Linter.Disable()
+var LintRules = []string{
+	RuleDuplicateKey,
+	RuleUnresolvedRef,
+	RuleTrailingSpace,
+	RuleKeyNaming,
+	RulePlainSecret,
+	RuleUnclosedBlock,
+	RuleMixedSeparator,
+	RuleNonASCII,
+}
+
+// DefaultKeyPattern for check the key name. eg: app.name, server.max-wait, users[0].name
+var DefaultKeyPattern = regexp.MustCompile(`^[A-Za-z_][\w-]*(\[\d+])*(\.[A-Za-z_][\w-]*(\[\d+])*)*$`)
+
+// LintIssue a rule violation found by the Linter
+type LintIssue struct {
+	// Rule name. eg: RuleDuplicateKey
+	Rule string
+	// Key of the entry, may be empty on syntax error.
+	Key string
+	// Line number in the source, start from 1. is 0 on unknown.
+	Line int
+	// Message of the issue
+	Message string
+}
+
+// String of the issue. eg: `line 3: duplicate key "name", first defined at line 1 (duplicate-key)`
+func (li *LintIssue) String() string {
+	return fmt.Sprintf("line %d: %s (%s)", li.Line, li.Message, li.Rule)
+}
+
+// Linter check the properties contents by rules, all builtin rules are enabled by default.
+//
+// Usage:
+//
+//	issues := properties.NewLinter().Disable(properties.RuleNonASCII).Lint(src)
+//	for _, issue := range issues {
+//		fmt.Println(issue)
+//	}
+type Linter struct {
+	// KeyPattern for check the key name on RuleKeyNaming. default: DefaultKeyPattern
+	KeyPattern *regexp.Regexp
+	// SecretKeys for check plain secrets on RulePlainSecret. default: DefaultSecretKeys
+	SecretKeys []string
+
+	opts     []OpFunc
+	disabled map[string]bool
+}
+
+// NewLinter instance. the options are used for parse the contents.
+func NewLinter(optFns ...OpFunc) *Linter {
+	return &Linter{
+		KeyPattern: DefaultKeyPattern,
+		opts:       optFns,
+		disabled:   make(map[string]bool),
+	}
+}
+
+// Lint check the contents with all builtin rules.
+func Lint(src []byte, optFns ...OpFunc) []*LintIssue {
+	return NewLinter(optFns...).Lint(src)
+}
+
+// Enable the rules
+func (l *Linter) Enable(rules ...string) *Linter {
+	for _, rule := range rules {
+		delete(l.disabled, rule)
+	}
+	return l
+}
+
+// Disable the rules. RuleSyntax can not be disabled.
+func (l *Linter) Disable(rules ...string) *Linter {
+	for _, rule := range rules {
+		l.disabled[rule] = true
+	}
+	return l
+}
+
+// Enabled check the rule is enabled
+func (l *Linter) Enabled(rule string) bool {
+	return rule == RuleSyntax || !l.disabled[rule]
+}
+
+// Lint check the contents, returns issues sorted by line.
+func (l *Linter) Lint(src []byte) []*LintIssue {
+	text := strings.ReplaceAll(string(src), "\r\n", "\n")
+	if strings.TrimSpace(text) == "" {
+		return nil
+	}
+
+	p := NewParser(l.opts...)
+	// lint the source only, not overlay env
+	p.opts.EnvPrefix = ""
+
+	lt := &linting{Linter: l, p: p, lines: strings.Split(text, "\n")}
+	if err := p.Parse(text); err != nil {
+		var se textscan.ErrScan
+		if errors.As(err, &se) {
+			lt.scanError(se)
+			return lt.issues
+		}
+		lt.addIssue(RuleSyntax, "", 0, err.Error())
+	}
+
+	// all entries in the source, includes the overridden entries.
+	lt.entries = append(lt.entries, p.overridden...)
+	for _, e := range p.entries {
+		if e.Line > 0 {
+			lt.entries = append(lt.entries, e)
+		}
+	}
+	sort.Slice(lt.entries, func(i, j int) bool {
+		return lt.entries[i].Line < lt.entries[j].Line
+	})
+
+	lt.checkDuplicate()
+	lt.checkSeparator()
+	for _, e := range lt.entries {
+		lt.checkEntry(e)
+	}
+
+	sort.SliceStable(lt.issues, func(i, j int) bool {
+		return lt.issues[i].Line < lt.issues[j].Line
+	})
+	return lt.issues
+}
+
+// linting state for once Lint
+type linting struct {
+	*Linter
+	p *Parser
+	// source lines
+	lines []string
+	// entries sorted by line
+	entries []*Entry
+	issues  []*LintIssue
+}
+
+func (lt *linting) addIssue(rule, key string, line int, format string, args ...any) {
+	if lt.Enabled(rule) {
+		msg := format
+		if len(args) > 0 {
+			msg = fmt.Sprintf(format, args...)
+		}
+		lt.issues = append(lt.issues, &LintIssue{Rule: rule, Key: key, Line: line, Message: msg})
+	}
+}
+
+func (lt *linting) scanError(se textscan.ErrScan) {
+	if se.Msg != textscan.ErrMLineValueNotEnd.Error() && se.Msg != ErrInlineNotEnd.Error() {
+		lt.addIssue(RuleSyntax, "", se.Line, "invalid line %q, %s", strings.TrimSpace(se.Text), se.Msg)
+		return
+	}
+
+	// find the start line of the multi line value
+	line := se.Line
+	for i := se.Line - 1; i >= 0 && i < len(lt.lines); i-- {
+		if lt.lines[i] == se.Text {
+			line = i + 1
+			break
+		}
+	}
+
+	key, _, _ := strings.Cut(se.Text, "=")
+	key = strings.TrimSpace(key)
+
+	rule := RuleUnclosedBlock
+	if !lt.Enabled(rule) {
+		rule = RuleSyntax
+	}
+	lt.addIssue(rule, key, line, "multi line value of key %q is not closed", key)
+}
+
+func (lt *linting) checkDuplicate() {
+	first := make(map[string]int, len(lt.entries))
+	for _, e := range lt.entries {
+		if line, ok := first[e.Key]; ok {
+			lt.addIssue(RuleDuplicateKey, e.Key, e.Line, "duplicate key %q, first defined at line %d", e.Key, line)
+		} else {
+			first[e.Key] = e.Line
+		}
+	}
+}
+
+// check the separator style by the most used style.
+func (lt *linting) checkSeparator() {
+	seps := make([]string, len(lt.entries))
+	counts := make(map[string]int)
+
+	var common string
+	for i, e := range lt.entries {
+		prefix := keyPrefixRegex(e.Key).FindString(lt.lines[e.Line-1])
+		if prefix == "" {
+			continue
+		}
+
+		sep := strings.TrimLeft(prefix, " \t")[len(e.Key):]
+		seps[i] = sep
+		counts[sep]++
+		if counts[sep] > counts[common] {
+			common = sep
+		}
+	}
+
+	if len(counts) < 2 {
+		return
+	}
+
+	for i, e := range lt.entries {
+		if sep := seps[i]; sep != "" && sep != common {
+			lt.addIssue(RuleMixedSeparator, e.Key, e.Line, "separator %q differs from the common style %q", sep, common)
+		}
+	}
+}
+
+func (lt *linting) checkEntry(e *Entry) {
+	first := lt.lines[e.Line-1]
+	end := e.lastLine()
+
+	// raw value of the first line. eg: "'''abc", "${name}"
+	var raw string
+	if pos := strings.IndexByte(first, '='); pos > -1 {
+		raw = strings.TrimSpace(first[pos+1:])
+	}
+	isBlock := strings.HasPrefix(raw, MultiLineValMarkS) || strings.HasPrefix(raw, MultiLineValMarkD)
+
+	if lt.KeyPattern != nil && !lt.KeyPattern.MatchString(e.Key) {
+		lt.addIssue(RuleKeyNaming, e.Key, e.Line, "key %q does not match the naming pattern %s", e.Key, lt.KeyPattern)
+	}
+
+	if e.Value != "" && !e.Encrypted() && !IsEncrypted(raw) && !strings.Contains(raw, VarRefStartChars) && IsSecretKey(e.Key, lt.SecretKeys...) {
+		lt.addIssue(RulePlainSecret, e.Key, e.Line, "plain text secret in key %q, use a ${ENV_NAME} reference or ENC(...) value instead", e.Key)
+	}
+
+	lt.checkRefs(e)
+
+	for ln := e.Line; ln <= end && ln <= len(lt.lines); ln++ {
+		line := lt.lines[ln-1]
+
+		// the inner lines of the block value are raw contents
+		inner := ln > e.Line && ln < end
+		if (!isBlock || !inner) && strings.TrimRight(line, " \t") != line {
+			lt.addIssue(RuleTrailingSpace, e.Key, ln, "trailing whitespace in value of key %q", e.Key)
+		}
+
+		if pos := firstNonASCII(line); pos > -1 {
+			r, _ := utf8.DecodeRuneInString(line[pos:])
+			lt.addIssue(RuleNonASCII, e.Key, ln, "non-ASCII char %q should be escaped as %s in a double-quoted value", r, unicodeEscape(r))
+		}
+
+		if ln > e.Line && kvLineRegex.MatchString(line) {
+			lt.addIssue(RuleUnclosedBlock, e.Key, e.Line, "multi line value of key %q looks not closed, it contains the key-value line %d", e.Key, ln)
+			// report once for the entry
+			isBlock = false
+			end = ln
+		}
+	}
+}
+
+// unicodeEscape of the rune, use UTF-16 surrogate pair on it's out of BMP. eg: \u00e9, \ud83d\ude00
+func unicodeEscape(r rune) string {
+	if r1, r2 := utf16.EncodeRune(r); r1 != '\uFFFD' {
+		return fmt.Sprintf("\\u%04x\\u%04x", r1, r2)
+	}
+	return fmt.Sprintf("\\u%04x", r)
+}
+
+// eg: "name = value", "  app.name=value"
+var kvLineRegex = regexp.MustCompile(`^\s*[A-Za-z_][\w.\-\[\]]*\s*=`)
+
+// eg: ${name}, ${name | default}
+var varRefRegex = regexp.MustCompile(`\$\{([^{}]*)}`)
+
+func (lt *linting) checkRefs(e *Entry) {
+	for _, ss := range varRefRegex.FindAllStringSubmatch(e.Value, -1) {
+		name, def, hasDef := strings.Cut(ss[1], "|")
+		name = strings.TrimSpace(name)
+		if hasDef && strings.TrimSpace(def) != "" {
+			continue
+		}
+
+		if _, ok := os.LookupEnv(name); ok {
+			continue
+		}
+
+		if _, ok := lt.p.smap[name]; ok {
+			lt.addIssue(RuleUnresolvedRef, e.Key, e.Line, "reference %q is not resolved, only support whole value reference to a key defined before", ss[0])
+		} else {
+			lt.addIssue(RuleUnresolvedRef, e.Key, e.Line, "reference %q is not defined", ss[0])
+		}
+	}
+}
+
+func firstNonASCII(s string) int {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,137 @@
+package properties_test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/gookit/goutil/testutil/assert"
+	"github.com/gookit/properties"
+)
+
+func lintRules(issues []*properties.LintIssue) map[string][]int {
+	mp := make(map[string][]int)
+	for _, issue := range issues {
+		mp[issue.Rule] = append(mp[issue.Rule], issue.Line)
+	}
+	return mp
+}
+
+func TestLint_clean(t *testing.T) {
+	src := `# app settings
+app.name = myapp
+app.host = ${APP_HOST | localhost}
+app.desc = ${app.name}
+app.multi = """line1
+line2
+"""
+users[0].name = inhere
+`
+	assert.Len(t, properties.Lint([]byte(src)), 0)
+	assert.Len(t, properties.Lint([]byte("  \n")), 0)
+}
+
+func TestLint_rules(t *testing.T) {
+	src := "name = inhere\n" +
+		"age = 23 \n" +
+		"name = tom\n" +
+		"db.password = pwd123\n" +
+		"db.token = ${DB_TOKEN_NOT_EXISTS}\n" +
+		"url = http://${app.host}:80\n" +
+		"Bad..Key = v\n" +
+		"city=北京\n" +
+		"desc = \"\"\"text\n" +
+		"other = value\n" +
+		"end\"\"\"\n"
+
+	issues := properties.Lint([]byte(src))
+	rules := lintRules(issues)
+
+	assert.Eq(t, []int{3}, rules[properties.RuleDuplicateKey])
+	assert.Eq(t, []int{2}, rules[properties.RuleTrailingSpace])
+	assert.Eq(t, []int{4}, rules[properties.RulePlainSecret])
+	assert.Eq(t, []int{5, 6}, rules[properties.RuleUnresolvedRef])
+	assert.Eq(t, []int{7}, rules[properties.RuleKeyNaming])
+	assert.Eq(t, []int{8}, rules[properties.RuleMixedSeparator])
+	assert.Eq(t, []int{8}, rules[properties.RuleNonASCII])
+	assert.Eq(t, []int{9}, rules[properties.RuleUnclosedBlock])
+
+	// sorted by line
+	assert.Eq(t, 2, issues[0].Line)
+	assert.Eq(t, `line 3: duplicate key "name", first defined at line 1 (duplicate-key)`, issues[1].String())
+	assert.Eq(t, "name", issues[1].Key)
+
+	for _, issue := range issues {
+		if issue.Rule == properties.RuleNonASCII {
+			assert.Eq(t, "non-ASCII char '北' should be escaped as \\u5317 in a double-quoted value", issue.Message)
+		}
+	}
+}
+
+func TestLint_nonASCII_escape(t *testing.T) {
+	src := "name = café\nemoji = 😀\n"
+	issues := properties.Lint([]byte(src))
+	assert.Len(t, issues, 2)
+	assert.Eq(t, "non-ASCII char 'é' should be escaped as \\u00e9 in a double-quoted value", issues[0].Message)
+	assert.Eq(t, "non-ASCII char '😀' should be escaped as \\ud83d\\ude00 in a double-quoted value", issues[1].Message)
+
+	// apply the advice
+	fixed := "name = \"caf\\u00e9\"\nemoji = \"\\ud83d\\ude00\"\n"
+	assert.Len(t, properties.Lint([]byte(fixed)), 0)
+
+	p, err := properties.Parse(fixed)
+	assert.NoErr(t, err)
+	assert.Eq(t, "café", p.Str("name"))
+	assert.Eq(t, "😀", p.Str("emoji"))
+
+	// invalid escape is kept
+	p, err = properties.Parse(`name = "a\u00zz\ud83d"`)
+	assert.NoErr(t, err)
+	assert.Eq(t, `a\u00zz\ud83d`, p.Str("name"))
+}
+
+func TestLinter_enable_disable(t *testing.T) {
+	src := []byte("name = inhere\nname=tom\n")
+
+	lt := properties.NewLinter()
+	assert.Len(t, lt.Lint(src), 2)
+
+	lt.Disable(properties.RuleDuplicateKey, properties.RuleSyntax)
+	assert.False(t, lt.Enabled(properties.RuleDuplicateKey))
+	assert.True(t, lt.Enabled(properties.RuleSyntax))
+
+	issues := lt.Lint(src)
+	assert.Len(t, issues, 1)
+	assert.Eq(t, properties.RuleMixedSeparator, issues[0].Rule)
+
+	lt.Enable(properties.RuleDuplicateKey)
+	assert.Len(t, lt.Lint(src), 2)
+
+	// custom key pattern and secret keys
+	lt = properties.NewLinter()
+	lt.KeyPattern = regexp.MustCompile(`^[a-z.]+$`)
+	lt.SecretKeys = []string{"pin"}
+	rules := lintRules(lt.Lint([]byte("app.name = a\napp.maxSize = 2\ncard.pin = 1234\ndb.password = abc\n")))
+	assert.Eq(t, []int{2}, rules[properties.RuleKeyNaming])
+	assert.Eq(t, []int{3}, rules[properties.RulePlainSecret])
+
+	// encrypted value is not plain secret
+	assert.Len(t, properties.Lint([]byte("db.password = ENC(YWJj)\n")), 0)
+}
+
+func TestLint_syntax(t *testing.T) {
+	issues := properties.Lint([]byte("name = inhere\ndesc = '''abc\nmore\n"))
+	assert.Len(t, issues, 1)
+	assert.Eq(t, properties.RuleUnclosedBlock, issues[0].Rule)
+	assert.Eq(t, 2, issues[0].Line)
+	assert.Eq(t, `multi line value of key "desc" is not closed`, issues[0].Message)
+
+	// fallback to syntax rule on disabled
+	issues = properties.NewLinter().Disable(properties.RuleUnclosedBlock).Lint([]byte("desc = '''abc\n"))
+	assert.Len(t, issues, 1)
+	assert.Eq(t, properties.RuleSyntax, issues[0].Rule)
+
+	issues = properties.Lint([]byte("name = inhere\ninvalid line\n"))
+	assert.Len(t, issues, 1)
+	assert.Eq(t, properties.RuleSyntax, issues[0].Rule)
+	assert.Eq(t, 2, issues[0].Line)
+}
@@ -0,0 +1,260 @@
+package properties
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// literalParser parse inline list and map literal value.
+//
+// Syntax:
+//
+//	list:   [a, "b,c", 'd', [1, 2], {k: v}]
+//	map:    {k1: v1, "k 2": [a, b], k3 = v3}
+//	escape: \, \] \} in bare string, \" \n \t in double-quoted string
+type literalParser struct {
+	src string
+	pos int
+	// infer type for bare string element
+	infer bool
+}
+
+// parseInlineValue parse inline list or map literal value. eg: [23, 34], {k: v}
+//
+// returns []any for list, map[string]any for map.
+func parseInlineValue(s string, infer bool) (any, error) {
+	lp := &literalParser{src: s, infer: infer}
+	lp.skipSpace()
+
+	var val any
+	var err error
+	switch lp.peek() {
+	case '[':
+		val, err = lp.parseList()
+	case '{':
+		val, err = lp.parseMap()
+	default:
+		return nil, errors.New("inline value must be start with [ or {")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	lp.skipSpace()
+	if lp.pos < len(lp.src) {
+		return nil, lp.errorf("unexpected chars %q", lp.src[lp.pos:])
+	}
+	return val, nil
+}
+
+func (lp *literalParser) errorf(format string, args ...any) error {
+	return fmt.Errorf("invalid inline value at %d: %s", lp.pos, fmt.Sprintf(format, args...))
+}
+
+func (lp *literalParser) peek() byte {
+	if lp.pos < len(lp.src) {
+		return lp.src[lp.pos]
+	}
+	return 0
+}
+
+func (lp *literalParser) skipSpace() {
+	for lp.pos < len(lp.src) {
+		switch lp.src[lp.pos] {
+		case ' ', '\t', '\n', '\r':
+			lp.pos++
+		default:
+			return
+		}
+	}
+}
+
+func (lp *literalParser) parseValue(stops string) (any, error) {
+	lp.skipSpace()
+	switch lp.peek() {
+	case '[':
+		return lp.parseList()
+	case '{':
+		return lp.parseMap()
+	case '"', '\'':
+		return lp.parseQuoted()
+	}
+
+	str := lp.parseBare(stops)
+	if lp.infer {
+		return inferValue(str), nil
+	}
+	return str, nil
+}
+
+func (lp *literalParser) parseList() ([]any, error) {
+	lp.pos++ // skip [
+	ls := make([]any, 0)
+
+	for {
+		lp.skipSpace()
+		switch lp.peek() {
+		case 0:
+			return nil, lp.errorf("list not closed")
+		case ']':
+			lp.pos++
+			return ls, nil
+		}
+
+		val, err := lp.parseValue(",]")
+		if err != nil {
+			return nil, err
+		}
+		ls = append(ls, val)
+
+		lp.skipSpace()
+		switch lp.peek() {
+		case ',':
+			lp.pos++
+		case ']':
+		default:
+			return nil, lp.errorf("expect , or ] in list")
+		}
+	}
+}
+
+func (lp *literalParser) parseMap() (map[string]any, error) {
+	lp.pos++ // skip {
+	mp := make(map[string]any)
+
+	for {
+		lp.skipSpace()
+		var key string
+		switch lp.peek() {
+		case 0:
+			return nil, lp.errorf("map not closed")
+		case '}':
+			lp.pos++
+			return mp, nil
+		case '"', '\'':
+			str, err := lp.parseQuoted()
+			if err != nil {
+				return nil, err
+			}
+			key = str
+		default:
+			key = lp.parseBare(":=,}")
+		}
+
+		lp.skipSpace()
+		if c := lp.peek(); c != ':' && c != '=' {
+			return nil, lp.errorf("expect : after map key %q", key)
+		}
+		lp.pos++
+
+		val, err := lp.parseValue(",}")
+		if err != nil {
+			return nil, err
+		}
+		mp[key] = val
+
+		lp.skipSpace()
+		switch lp.peek() {
+		case ',':
+			lp.pos++
+		case '}':
+		default:
+			return nil, lp.errorf("expect , or } in map")
+		}
+	}
+}
+
+func (lp *literalParser) parseQuoted() (string, error) {
+	quote := lp.src[lp.pos]
+	lp.pos++
+
+	start := lp.pos
+	for lp.pos < len(lp.src) {
+		c := lp.src[lp.pos]
+		if c == '\\' && quote == QuoteDouble {
+			lp.pos += 2
+			continue
+		}
+
+		if c == quote {
+			str := lp.src[start:lp.pos]
+			lp.pos++
+			if quote == QuoteDouble {
+				str = unescapeValue(str)
+			}
+			return str, nil
+		}
+		lp.pos++
+	}
+	return "", lp.errorf("quoted string not closed")
+}
+
+// parse bare string until meet one of stops char.
+func (lp *literalParser) parseBare(stops string) string {
+	var sb strings.Builder
+	for lp.pos < len(lp.src) {
+		c := lp.src[lp.pos]
+		if c == '\\' && lp.pos+1 < len(lp.src) {
+			sb.WriteByte(lp.src[lp.pos+1])
+			lp.pos += 2
+			continue
+		}
+
+		if strings.IndexByte(stops, c) > -1 || c == '\n' {
+			break
+		}
+		sb.WriteByte(c)
+		lp.pos++
+	}
+	return strings.TrimSpace(sb.String())
+}
+
+// isInlineStart check value is start of a multi line inline value. eg: "[", "[a, b,"
+func isInlineStart(s string) bool {
+	ln := len(s)
+	if ln == 0 || (s[0] != '[' && s[0] != '{') {
+		return false
+	}
+
+	last := s[ln-1]
+	return (last == '[' || last == '{' || last == ',') && !inlineClosed(s)
+}
+
+// inlineClosed check all brackets are closed in the inline value.
+func inlineClosed(s string) bool {
+	var depth int
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c == '\\' {
+			i++
+			continue
+		}
+
+		if quote > 0 {
+			if c == quote {
+				quote = 0
+			}
+			continue
+		}
+
+		switch c {
+		case '"', '\'':
+			quote = c
+		case '[', '{':
+			depth++
+		case ']', '}':
+			depth--
+		}
+	}
+	return depth <= 0
+}
+
+// quote string element of inline value on need.
+func quoteInlineStr(s string) string {
+	if s == "" || s != strings.TrimSpace(s) || strings.ContainsAny(s, "[]{},:=\"'\\\n\t") {
+		return quoteValue(s, QuoteDouble)
+	}
+	return s
+}
@@ -0,0 +1,214 @@
+package properties
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gookit/goutil/maputil"
+)
+
+// MergeStrategy on the key exists in both dst and src
+type MergeStrategy uint8
+
+// merge strategies
+const (
+	// MergeOverride override the dst value by src value
+	MergeOverride MergeStrategy = iota
+	// MergeKeepExisting keep the dst value
+	MergeKeepExisting
+	// MergeErrorOnConflict returns error on the values are different
+	MergeErrorOnConflict
+)
+
+// ErrMergeConflict error on use MergeErrorOnConflict
+var ErrMergeConflict = errors.New("merge conflict")
+
+// Merge the src data to dst, the Data, SMap, comments and entries will be merged.
+//
+// Nested maps are merged by leaf keys. Slice is merged as a whole value, like Spring:
+// on override, the dst slice will be replaced by the src slice.
+//
+// The scalar value and the nested keys of the same name are conflicted. eg: "top" and "top.sub"
+//
+// The dst will not be modified on error. eg: has conflict on use MergeErrorOnConflict
+func Merge(dst, src *Parser, strategy MergeStrategy) error {
+	units := mergeUnits(src)
+	names := make([]string, 0, len(units))
+	for name := range units {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	// check conflicts before modify the dst
+	if strategy == MergeErrorOnConflict {
+		for _, name := range names {
+			old := unitKeys(dst, name)
+			if len(old) > 0 && !sameUnit(dst, old, src, units[name]) {
+				return fmt.Errorf("%w on key %q", ErrMergeConflict, name)
+			}
+		}
+	}
+
+	// merge to a copy, swap to dst on success
+	np := dst.rekey(func(key string) (string, bool) {
+		return key, true
+	})
+	if np.err != nil {
+		return np.err
+	}
+
+	for _, name := range names {
+		if old := unitKeys(np, name); len(old) > 0 {
+			if strategy != MergeOverride {
+				continue
+			}
+			np.removeKeys(name, old)
+		}
+
+		for _, key := range units[name] {
+			val, ok := maputil.GetByPath(pathOfKey(key), src.Data)
+			if !ok {
+				continue
+			}
+
+			if e, ok := src.entries[key]; ok {
+				ne := *e
+				np.entries[key] = &ne
+			}
+			if cmt, ok := src.comments[key]; ok {
+				np.comments[key] = cmt
+			}
+			np.smap[key] = src.smap[key]
+			np.collect(key, cloneValue(val))
+			if np.err != nil {
+				return np.err
+			}
+		}
+	}
+
+	dst.Data = np.Data
+	dst.smap = np.smap
+	dst.comments = np.comments
+	dst.entries = np.entries
+	return nil
+}
+
+// group the keys by merge unit, the indexed keys of a slice is one unit.
+//
+// eg: "tags[0]", "tags[1]" -> "tags"
+func mergeUnits(p *Parser) map[string][]string {
+	units := make(map[string][]string)
+	for _, key := range p.keysByLine() {
+		name := unitName(key)
+		units[name] = append(units[name], key)
+	}
+	return units
+}
+
+func unitName(key string) string {
+	if pos := strings.IndexByte(key, '['); pos > 0 {
+		return key[:pos]
+	}
+	return key
+}
+
+// find the exists keys of the merge unit, include the nested keys and the parent key of a scalar value.
+//
+// eg: "top" -> "top", "top[0]", "top.sub"; "top.sub" -> "top" on it's not a map.
+func unitKeys(p *Parser, name string) []string {
+	var keys []string
+	for key := range p.smap {
+		if key == name || strings.HasPrefix(key, name+"[") || strings.HasPrefix(key, name+".") {
+			keys = append(keys, key)
+			continue
+		}
+
+		if strings.HasPrefix(name, key+".") {
+			val, _ := maputil.GetByPath(pathOfKey(key), p.Data)
+			if _, isMap := asMap(val); !isMap {
+				keys = append(keys, key)
+			}
+		}
+	}
+	return keys
+}
+
+func sameUnit(a *Parser, aKeys []string, b *Parser, bKeys []string) bool {
+	if len(aKeys) != len(bKeys) {
+		return false
+	}
+
+	for _, key := range bKeys {
+		if av, ok := a.smap[key]; !ok || av != b.smap[key] {
+			return false
+		}
+	}
+	return true
+}
+
+// remove the keys of the merge unit.
+func (p *Parser) removeKeys(name string, keys []string) {
+	for _, key := range keys {
+		delete(p.smap, key)
+		delete(p.entries, key)
+		delete(p.comments, key)
+
+		// the parent key of the unit. eg: "top" of "top.sub"
+		if _, ok := trimKeyPrefix(key, name); !ok && key != name {
+			deleteByPath(p.Data, unitName(key))
+		}
+	}
+	deleteByPath(p.Data, name)
+}
+
+// delete value from the nested map by key path. eg: "top.sub.key"
+func deleteByPath(data map[string]any, path string) {
+	if _, ok := data[path]; ok {
+		delete(data, path)
+		return
+	}
+
+	nodes := strings.Split(path, ".")
+	last := len(nodes) - 1
+	for _, node := range nodes[:last] {
+		sub, ok := data[node].(map[string]any)
+		if !ok {
+			return
+		}
+		data = sub
+	}
+	delete(data, nodes[last])
+}
+
+// cloneValue deep copy the map and slice value.
+func cloneValue(val any) any {
+	switch typVal := val.(type) {
+	case map[string]any:
+		mp := make(map[string]any, len(typVal))
+		for k, v := range typVal {
+			mp[k] = cloneValue(v)
+		}
+		return mp
+	case maputil.Data:
+		return cloneValue(map[string]any(typVal))
+	case []any:
+		ls := make([]any, len(typVal))
+		for i, v := range typVal {
+			ls[i] = cloneValue(v)
+		}
+		return ls
+	case []string:
+		ls := make([]string, len(typVal))
+		copy(ls, typVal)
+		return ls
+	case []map[string]any:
+		ls := make([]map[string]any, len(typVal))
+		for i, mp := range typVal {
+			ls[i] = cloneValue(mp).(map[string]any)
+		}
+		return ls
+	}
+	return val
+}
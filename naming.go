@@ -0,0 +1,137 @@
+package properties
+
+import (
+	"reflect"
+	"strings"
+	"unicode"
+
+	"github.com/go-viper/mapstructure/v2"
+	"github.com/gookit/goutil/maputil"
+)
+
+// NameStrategy convert the Go struct field name to properties key name.
+//
+// It is used for untagged fields on encode and decode.
+type NameStrategy func(name string) string
+
+// builtin name strategies
+var (
+	// KebabCase eg: MaxWait -> max-wait
+	KebabCase NameStrategy = func(name string) string {
+		return joinWords(name, "-")
+	}
+	// SnakeCase eg: MaxWait -> max_wait
+	SnakeCase NameStrategy = func(name string) string {
+		return joinWords(name, "_")
+	}
+	// DottedLower eg: MaxWait -> max.wait
+	DottedLower NameStrategy = func(name string) string {
+		return joinWords(name, ".")
+	}
+	// LowerCamel eg: MaxWait -> maxWait, UserID -> userId
+	LowerCamel NameStrategy = func(name string) string {
+		words := splitWords(name)
+		for i, word := range words {
+			word = strings.ToLower(word)
+			if i > 0 {
+				word = strings.ToUpper(word[:1]) + word[1:]
+			}
+			words[i] = word
+		}
+		return strings.Join(words, "")
+	}
+)
+
+func joinWords(name, sep string) string {
+	words := splitWords(name)
+	for i, word := range words {
+		words[i] = strings.ToLower(word)
+	}
+	return strings.Join(words, sep)
+}
+
+// splitWords split the Go field name to words. eg: "HTTPServerID" -> ["HTTP", "Server", "ID"]
+func splitWords(name string) []string {
+	rs := []rune(name)
+	ln := len(rs)
+
+	var words []string
+	var start int
+	for i := 1; i < ln; i++ {
+		prev, cur := rs[i-1], rs[i]
+		switch {
+		case cur == '_' || cur == '-' || cur == '.':
+			if i > start {
+				words = append(words, string(rs[start:i]))
+			}
+			start = i + 1
+		case unicode.IsUpper(cur) && (unicode.IsLower(prev) || unicode.IsDigit(prev)):
+			// eg: maxWait, port2Num
+			words = append(words, string(rs[start:i]))
+			start = i
+		case unicode.IsUpper(prev) && unicode.IsUpper(cur) && i+1 < ln && unicode.IsLower(rs[i+1]):
+			// eg: HTTPServer -> HTTP, Server
+			words = append(words, string(rs[start:i]))
+			start = i
+		}
+	}
+
+	if start < ln {
+		words = append(words, string(rs[start:]))
+	}
+	return words
+}
+
+// MatchNameFunc returns a func for mapstructure.DecoderConfig.MatchName,
+// will match the map key by the name strategy and ignore case.
+func MatchNameFunc(strategy NameStrategy) func(mapKey, fieldName string) bool {
+	return func(mapKey, fieldName string) bool {
+		return strings.EqualFold(mapKey, fieldName) || mapKey == strategy(fieldName)
+	}
+}
+
+// NestedNameHookFunc returns a mapstructure.DecodeHookFunc for the name strategy
+// that generates dotted key. eg: DottedLower
+//
+// It will collect the nested value as flat key for untagged fields. eg: {max: {wait: 3}} -> {"max.wait": 3}
+func NestedNameHookFunc(strategy NameStrategy, tagName string) mapstructure.DecodeHookFunc {
+	return func(f reflect.Type, t reflect.Type, data any) (any, error) {
+		if t.Kind() != reflect.Struct {
+			return data, nil
+		}
+
+		mp, ok := data.(map[string]any)
+		if !ok {
+			if dmp, isData := data.(maputil.Data); isData {
+				mp = dmp
+			} else {
+				return data, nil
+			}
+		}
+
+		var newMp map[string]any
+		for i := 0; i < t.NumField(); i++ {
+			sf := t.Field(i)
+			if !sf.IsExported() || sf.Tag.Get(tagName) != "" {
+				continue
+			}
+
+			key := strategy(sf.Name)
+			if !strings.ContainsRune(key, '.') {
+				continue
+			}
+
+			if val, ok := maputil.GetByPath(key, mp); ok {
+				if newMp == nil {
+					newMp = maputil.CloneAnyMap(mp)
+				}
+				newMp[key] = val
+			}
+		}
+
+		if newMp == nil {
+			return data, nil
+		}
+		return newMp, nil
+	}
+}
@@ -0,0 +1,89 @@
+package properties_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gookit/goutil/testutil/assert"
+	"github.com/gookit/properties"
+)
+
+func TestNameStrategy(t *testing.T) {
+	tests := []struct {
+		name string
+		want [4]string // kebab, snake, lowerCamel, dotted
+	}{
+		{"MaxWait", [4]string{"max-wait", "max_wait", "maxWait", "max.wait"}},
+		{"UserID", [4]string{"user-id", "user_id", "userId", "user.id"}},
+		{"HTTPServer", [4]string{"http-server", "http_server", "httpServer", "http.server"}},
+		{"Port2Num", [4]string{"port2-num", "port2_num", "port2Num", "port2.num"}},
+		{"Name", [4]string{"name", "name", "name", "name"}},
+	}
+
+	for _, tt := range tests {
+		assert.Eq(t, tt.want[0], properties.KebabCase(tt.name))
+		assert.Eq(t, tt.want[1], properties.SnakeCase(tt.name))
+		assert.Eq(t, tt.want[2], properties.LowerCamel(tt.name))
+		assert.Eq(t, tt.want[3], properties.DottedLower(tt.name))
+	}
+}
+
+type namingPool struct {
+	MaxWait  time.Duration
+	MinIdle  int
+	PoolName string `properties:"name"`
+}
+
+func TestNameStrategy_encodeDecode(t *testing.T) {
+	strategies := []properties.NameStrategy{
+		properties.KebabCase,
+		properties.SnakeCase,
+		properties.LowerCamel,
+		properties.DottedLower,
+	}
+
+	pool := &namingPool{MaxWait: 3 * time.Second, MinIdle: 2, PoolName: "main"}
+	for _, strategy := range strategies {
+		e := properties.NewEncoder()
+		e.NameStrategy = strategy
+		bs, err := e.Encode(pool)
+		assert.NoErr(t, err)
+
+		str := string(bs)
+		assert.StrContains(t, str, strategy("MaxWait")+"=3s\n")
+		assert.StrContains(t, str, strategy("MinIdle")+"=2\n")
+		assert.StrContains(t, str, "name=main\n")
+
+		p := properties.NewParser(properties.WithNameStrategy(strategy))
+		assert.NoErr(t, p.ParseBytes(bs))
+
+		cfg := &namingPool{}
+		assert.NoErr(t, p.Decode(cfg))
+		assert.Eq(t, pool, cfg)
+	}
+}
+
+func TestNameStrategy_decode(t *testing.T) {
+	text := `
+spring.redis.max-wait = 3s
+spring.redis.min-idle = 2
+spring.redis.name = main
+`
+
+	p := properties.NewParser(properties.WithNameStrategy(properties.KebabCase))
+	assert.NoErr(t, p.Parse(text))
+
+	cfg := &namingPool{}
+	assert.NoErr(t, p.MapStruct("spring.redis", cfg))
+	assert.Eq(t, 3*time.Second, cfg.MaxWait)
+	assert.Eq(t, 2, cfg.MinIdle)
+	assert.Eq(t, "main", cfg.PoolName)
+
+	// without name strategy
+	p = properties.NewParser()
+	assert.NoErr(t, p.Parse(text))
+
+	cfg = &namingPool{}
+	assert.NoErr(t, p.MapStruct("spring.redis", cfg))
+	assert.Eq(t, time.Duration(0), cfg.MaxWait)
+}
@@ -16,7 +16,9 @@ type Options struct {
 	ParseEnv bool
 	// ParseVar reference. eg: "${other.var.name}". default: true
 	ParseVar bool
-	// ParseTime string on binding struct. eg: 3s -> 3*time.Second
+	// ParseTime string on binding struct. default: true
+	//
+	// eg: 3s -> 3*time.Second, "2024-01-02" -> time.Time
 	ParseTime bool
 	// TagName for binding data to struct. default: properties
 	TagName string
@@ -27,10 +29,61 @@ type Options struct {
 	//
 	// allow chars: #, //
 	InlineComment bool
-	// InlineSlice support parse the inline slice. eg: [23, 34]. default: false
+	// InlineSlice support parse the inline list and map. default: false
+	//
+	// eg: [23, 34], ["a,b", c], {k1: v1, k2: [a, b]}
+	//
+	// Value will be parsed to []any or map[string]any, allow multi line value:
+	//
+	//	key = [
+	//	  a,
+	//	  b
+	//	]
 	InlineSlice bool
+	// BareKey allow the key only line, the value will be empty. default: false
+	//
+	// eg: "key" -> "key=", it is the NilKeyOnly style output of the Encoder.
+	//
+	// NOTE: the line contains whitespace is still invalid. eg: "invalid line"
+	BareKey bool
+	// InferType infer value type on collect to Data. default: false
+	//
+	// eg: "123" -> int64(123), "1.5" -> float64(1.5), "true" -> true, "null" -> nil
+	//
+	// NOTE: quoted value will keep as string. eg: "123"
+	InferType bool
 	// MapStructConfig for binding data to struct.
+	//
+	// NOTE: if MapStructConfig.DecodeHook is set, will be chained before the builtin hooks.
 	MapStructConfig mapstructure.DecoderConfig
+	// DecodeHooks custom decode hooks on binding struct.
+	// will be chained after MapStructConfig.DecodeHook, before the builtin hooks.
+	DecodeHooks []mapstructure.DecodeHookFunc
+	// TimeLayouts for parse string to time.Time. default: DefaultTimeLayouts
+	TimeLayouts []string
+	// NameStrategy for match key name of untagged fields on binding struct.
+	//
+	// eg: KebabCase, SnakeCase, LowerCamel, DottedLower
+	NameStrategy NameStrategy
+	// RelaxedBinding binding struct with relaxed key name, like Spring Boot. default: false
+	//
+	// eg: max-wait, maxWait, max_wait, MAX_WAIT will be matched to the field MaxWait.
+	// And env var style key will be split as nested keys. eg: SPRING_REDIS_HOST -> spring.redis.host
+	RelaxedBinding bool
+	// EnvPrefix overlay the env vars that start with the prefix onto keys after parsed. default: ""
+	//
+	// eg: with prefix "APP_", APP_SERVER_PORT will override server.port
+	EnvPrefix string
+	// EnvKeyMapper convert the env var name to key path. default: DefaultEnvKeyMapper
+	EnvKeyMapper EnvKeyMapper
+	// Validator for validate the struct after MapStruct. default: nil
+	//
+	// eg: NewTagValidator() validate by the tag rules: `validate:"required,min=1,max=65535"`
+	Validator Validator
+	// Decryptor for decrypt the ENC(...) value on parse. default: nil
+	//
+	// eg: NewAESGCM(key) decrypt "ENC(base64...)" value, base64 contents is nonce + ciphertext.
+	Decryptor Decryptor
 	// BeforeCollect value handle func, you can return a new value.
 	BeforeCollect func(name string, val any) any
 }
@@ -42,18 +95,48 @@ func (opts *Options) makeDecoderConfig() *mapstructure.DecoderConfig {
 		decConf.TagName = opts.TagName
 	}
 
-	// parse time string on binding to struct
-	if opts.ParseTime || decConf.DecodeHook == nil {
-		decConf.DecodeHook = ValDecodeHookFunc()
+	if decConf.MatchName == nil {
+		if opts.RelaxedBinding {
+			decConf.MatchName = relaxedMatchName
+		} else if opts.NameStrategy != nil {
+			decConf.MatchName = MatchNameFunc(opts.NameStrategy)
+		}
 	}
 
+	decConf.DecodeHook = opts.makeDecodeHook()
 	return &decConf
 }
 
+func (opts *Options) tagName() string {
+	if opts.MapStructConfig.TagName != "" {
+		return opts.MapStructConfig.TagName
+	}
+	return opts.TagName
+}
+
+// makeDecodeHook chain decode hooks by order:
+//
+//	name strategy hook -> MapStructConfig.DecodeHook -> DecodeHooks -> builtin hooks
+func (opts *Options) makeDecodeHook() mapstructure.DecodeHookFunc {
+	hooks := make([]mapstructure.DecodeHookFunc, 0, len(opts.DecodeHooks)+9)
+	if opts.NameStrategy != nil {
+		hooks = append(hooks, NestedNameHookFunc(opts.NameStrategy, opts.tagName()))
+	}
+	if opts.MapStructConfig.DecodeHook != nil {
+		hooks = append(hooks, opts.MapStructConfig.DecodeHook)
+	}
+	hooks = append(hooks, opts.DecodeHooks...)
+
+	// builtin hooks, skip the duration and time hooks on ParseTime is false
+	hooks = append(hooks, builtinHooks(opts.ParseTime, opts.TimeLayouts...)...)
+	return mapstructure.ComposeDecodeHookFunc(hooks...)
+}
+
 func newDefaultOption() *Options {
 	return &Options{
-		ParseVar: true,
-		TagName:  DefaultTagName,
+		ParseVar:  true,
+		ParseTime: true,
+		TagName:   DefaultTagName,
 		// map struct config
 		MapStructConfig: mapstructure.DecoderConfig{
 			TagName: DefaultTagName,
@@ -78,11 +161,78 @@ func ParseTime(opts *Options) {
 	opts.ParseTime = true
 }
 
+// WithDecodeHooks append custom decode hooks on binding struct.
+func WithDecodeHooks(hooks ...mapstructure.DecodeHookFunc) OpFunc {
+	return func(opts *Options) {
+		opts.DecodeHooks = append(opts.DecodeHooks, hooks...)
+	}
+}
+
+// WithTimeLayouts custom layouts for parse time string to time.Time
+func WithTimeLayouts(layouts ...string) OpFunc {
+	return func(opts *Options) {
+		opts.TimeLayouts = layouts
+	}
+}
+
+// RelaxedBinding open relaxed binding for binding struct.
+func RelaxedBinding(opts *Options) {
+	opts.RelaxedBinding = true
+}
+
+// WithEnvOverlay overlay the env vars that start with the prefix onto keys after parsed.
+//
+// mapper is optional, default use DefaultEnvKeyMapper
+func WithEnvOverlay(prefix string, mapper ...EnvKeyMapper) OpFunc {
+	return func(opts *Options) {
+		opts.EnvPrefix = prefix
+		if len(mapper) > 0 {
+			opts.EnvKeyMapper = mapper[0]
+		}
+	}
+}
+
+// WithValidator set the validator for validate the struct after MapStruct.
+func WithValidator(v Validator) OpFunc {
+	return func(opts *Options) {
+		opts.Validator = v
+	}
+}
+
+// ValidateByTag use the builtin TagValidator for validate the struct after MapStruct.
+func ValidateByTag(opts *Options) {
+	opts.Validator = NewTagValidator()
+}
+
+// WithDecryptor set the decryptor for decrypt the ENC(...) value on parse.
+func WithDecryptor(d Decryptor) OpFunc {
+	return func(opts *Options) {
+		opts.Decryptor = d
+	}
+}
+
+// WithNameStrategy set name strategy for match key name of untagged fields on binding struct.
+func WithNameStrategy(strategy NameStrategy) OpFunc {
+	return func(opts *Options) {
+		opts.NameStrategy = strategy
+	}
+}
+
 // ParseInlineSlice open parse inline slice
 func ParseInlineSlice(opts *Options) {
 	opts.InlineSlice = true
 }
 
+// ParseBareKey allow the key only line, the value will be empty.
+func ParseBareKey(opts *Options) {
+	opts.BareKey = true
+}
+
+// InferType open infer value type on collect to Data.
+func InferType(opts *Options) {
+	opts.InferType = true
+}
+
 // WithTagName custom tag name on binding struct
 func WithTagName(tagName string) OpFunc {
 	return func(opts *Options) {
@@ -1,6 +1,7 @@
 package properties_test
 
 import (
+	"reflect"
 	"testing"
 	"time"
 
@@ -96,3 +97,68 @@ expire = 3s
 	assert.Eq(t, 3*time.Second, cfg.Expire)
 
 }
+
+func TestOptions_DecodeHooks(t *testing.T) {
+	text := `
+name = inhere
+level = WARN
+expire = 3s
+`
+
+	type Level int
+	type MyConf struct {
+		Name   string        `properties:"name"`
+		Level  Level         `properties:"level"`
+		Expire time.Duration `properties:"expire"`
+	}
+
+	levelHook := func(f reflect.Type, t reflect.Type, data any) (any, error) {
+		if f.Kind() == reflect.String && t == reflect.TypeOf(Level(0)) {
+			if data.(string) == "WARN" {
+				return Level(3), nil
+			}
+		}
+		return data, nil
+	}
+
+	// custom hook on MapStructConfig.DecodeHook
+	p := properties.NewParser(func(opts *properties.Options) {
+		opts.MapStructConfig.DecodeHook = levelHook
+	})
+	assert.NoErr(t, p.Parse(text))
+
+	cfg := &MyConf{}
+	assert.NoErr(t, p.Decode(cfg))
+	assert.Eq(t, Level(3), cfg.Level)
+	assert.Eq(t, 3*time.Second, cfg.Expire)
+
+	// custom hook by WithDecodeHooks
+	p = properties.NewParser(properties.ParseTime, properties.WithDecodeHooks(levelHook))
+	assert.NoErr(t, p.Parse(text))
+
+	cfg = &MyConf{}
+	assert.NoErr(t, p.Decode(cfg))
+	assert.Eq(t, Level(3), cfg.Level)
+	assert.Eq(t, 3*time.Second, cfg.Expire)
+}
+
+func TestOptions_ParseTime_disable(t *testing.T) {
+	type MyConf struct {
+		Expire time.Duration `properties:"expire"`
+	}
+
+	p := properties.NewParser(func(opts *properties.Options) {
+		opts.ParseTime = false
+	})
+	assert.NoErr(t, p.Parse("expire = 3s"))
+	assert.Err(t, p.Decode(&MyConf{}))
+
+	p = properties.NewParser(properties.WithTimeLayouts("02/01/2006"))
+	assert.NoErr(t, p.Parse("day = 25/12/2023"))
+
+	cfg := &struct {
+		Day time.Time `properties:"day"`
+	}{}
+	assert.NoErr(t, p.Decode(cfg))
+	assert.Eq(t, 25, cfg.Day.Day())
+}
@@ -0,0 +1,173 @@
+package properties
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gookit/goutil/maputil"
+)
+
+// OrderedMap a map that keeps the insertion order of the keys.
+//
+// Use Parser.OrderedData() to get the nested data in file order.
+type OrderedMap struct {
+	keys   []string
+	values map[string]any
+}
+
+// NewOrderedMap instance
+func NewOrderedMap() *OrderedMap {
+	return &OrderedMap{values: make(map[string]any)}
+}
+
+// Set the value of the key, a new key will be appended to the end.
+func (om *OrderedMap) Set(key string, val any) {
+	if _, ok := om.values[key]; !ok {
+		om.keys = append(om.keys, key)
+	}
+	om.values[key] = val
+}
+
+// Get the value of the key
+func (om *OrderedMap) Get(key string) (any, bool) {
+	val, ok := om.values[key]
+	return val, ok
+}
+
+// Keys in the insertion order
+func (om *OrderedMap) Keys() []string {
+	keys := make([]string, len(om.keys))
+	copy(keys, om.keys)
+	return keys
+}
+
+// Len of the map
+func (om *OrderedMap) Len() int {
+	return len(om.keys)
+}
+
+// Range iterate the map in order, stop on fn returns false.
+func (om *OrderedMap) Range(fn func(key string, val any) bool) {
+	for _, key := range om.keys {
+		if !fn(key, om.values[key]) {
+			return
+		}
+	}
+}
+
+// MarshalJSON to JSON object, will keep the order of the keys.
+func (om *OrderedMap) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, key := range om.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+
+		kb, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		vb, err := json.Marshal(om.values[key])
+		if err != nil {
+			return nil, err
+		}
+
+		buf.Write(kb)
+		buf.WriteByte(':')
+		buf.Write(vb)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// Range iterate the keys in file order, stop on fn returns false.
+//
+// The comment is the raw comments above the key, line is 0 on the key is not from the source. eg: Set, OverlayEnv
+func (p *Parser) Range(fn func(key, value, comment string, line int) bool) {
+	for _, key := range p.FlatKeys() {
+		if !fn(key, p.smap[key], p.comments[key], p.lineOf(key)) {
+			return
+		}
+	}
+}
+
+// OrderedData get the nested data in file order, the maps are converted to *OrderedMap.
+//
+// It is useful for display or re-encode the data in file order. eg:
+//
+//	bs, err := json.Marshal(p.OrderedData())
+func (p *Parser) OrderedData() *OrderedMap {
+	// rank of the key path, is the index of the first key under it.
+	rank := make(map[string]int)
+	for i, key := range p.FlatKeys() {
+		path := pathOfKey(key)
+		for {
+			if _, ok := rank[path]; !ok {
+				rank[path] = i
+			}
+
+			pos := strings.LastIndexByte(path, '.')
+			if pos < 0 {
+				break
+			}
+			path = path[:pos]
+		}
+	}
+
+	return orderedValue(map[string]any(p.Data), "", rank).(*OrderedMap)
+}
+
+func orderedValue(val any, path string, rank map[string]int) any {
+	switch typVal := val.(type) {
+	case maputil.Data:
+		return orderedValue(map[string]any(typVal), path, rank)
+	case map[string]any:
+		keys := make([]string, 0, len(typVal))
+		for key := range typVal {
+			keys = append(keys, key)
+		}
+
+		// sort by rank, the keys without rank are sorted by name at the end.
+		sort.Slice(keys, func(i, j int) bool {
+			ri, iok := rank[joinPath(path, keys[i])]
+			rj, jok := rank[joinPath(path, keys[j])]
+			if iok != jok {
+				return iok
+			}
+			if ri != rj {
+				return ri < rj
+			}
+			return keys[i] < keys[j]
+		})
+
+		om := NewOrderedMap()
+		for _, key := range keys {
+			om.Set(key, orderedValue(typVal[key], joinPath(path, key), rank))
+		}
+		return om
+	case []any:
+		ls := make([]any, len(typVal))
+		for i, v := range typVal {
+			ls[i] = orderedValue(v, joinPath(path, strconv.Itoa(i)), rank)
+		}
+		return ls
+	case []map[string]any:
+		ls := make([]any, len(typVal))
+		for i, v := range typVal {
+			ls[i] = orderedValue(v, joinPath(path, strconv.Itoa(i)), rank)
+		}
+		return ls
+	}
+	return val
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
@@ -0,0 +1,106 @@
+package properties_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/gookit/goutil/testutil/assert"
+	"github.com/gookit/properties"
+)
+
+var orderedText = `
+# app name
+name = inhere
+server.port = 8080
+age = 23
+server.host = localhost
+users[0].name = tom
+users[0].age = 20
+users[1].name = jerry
+db = "mysql"
+`
+
+func TestParser_Keys_Range(t *testing.T) {
+	p, err := properties.Parse(orderedText)
+	assert.NoErr(t, err)
+	p.Set("added", "value")
+	assert.NoErr(t, p.OverlayEnvMap("APP_", map[string]string{"APP_AGE": "25"}))
+
+	assert.Eq(t, []string{
+		"name",
+		"server.port",
+		"age",
+		"server.host",
+		"users[0].name",
+		"users[0].age",
+		"users[1].name",
+		"db",
+	}, p.FlatKeys())
+
+	var keys []string
+	var lines []int
+	p.Range(func(key, value, comment string, line int) bool {
+		if key == "name" {
+			assert.Eq(t, "inhere", value)
+			assert.Eq(t, "# app name", comment)
+		}
+		keys = append(keys, key)
+		lines = append(lines, line)
+		return key != "server.host"
+	})
+	assert.Eq(t, []string{"name", "server.port", "age", "server.host"}, keys)
+	assert.Eq(t, []int{3, 4, 5, 6}, lines)
+}
+
+func TestParser_OrderedData(t *testing.T) {
+	p, err := properties.Parse(orderedText)
+	assert.NoErr(t, err)
+	p.Set("added", "value")
+
+	om := p.OrderedData()
+	assert.Eq(t, []string{"name", "server", "age", "users", "db", "added"}, om.Keys())
+	assert.Eq(t, 6, om.Len())
+
+	val, ok := om.Get("server")
+	assert.True(t, ok)
+	assert.Eq(t, []string{"port", "host"}, val.(*properties.OrderedMap).Keys())
+
+	bs, err := json.Marshal(om)
+	assert.NoErr(t, err)
+	assert.Eq(t, `{"name":"inhere","server":{"port":"8080","host":"localhost"},"age":"23",`+
+		`"users":[{"name":"tom","age":"20"},{"name":"jerry"}],"db":"mysql","added":"value"}`, string(bs))
+
+	var n int
+	om.Range(func(key string, val any) bool {
+		n++
+		return n < 2
+	})
+	assert.Eq(t, 2, n)
+}
+
+func TestEncoder_KeepOrder(t *testing.T) {
+	p, err := properties.Parse(orderedText)
+	assert.NoErr(t, err)
+
+	enc := properties.NewEncoder()
+	enc.KeepOrder = true
+	bs, err := enc.Encode(p)
+	assert.NoErr(t, err)
+	assert.Eq(t, `name=inhere
+server.port=8080
+server.host=localhost
+age=23
+users[0].name=tom
+users[0].age=20
+users[1].name=jerry
+db="mysql"
+`, string(bs))
+
+	om := properties.NewOrderedMap()
+	om.Set("b", 1)
+	om.Set("a", map[string]any{"y": 2, "x": 1})
+	om.Set("b", 3)
+	bs, err = properties.Encode(om)
+	assert.NoErr(t, err)
+	assert.Eq(t, "b=3\na.x=1\na.y=2\n", string(bs))
+}
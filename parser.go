@@ -3,7 +3,9 @@ package properties
 import (
 	"bytes"
 	"errors"
+	"fmt"
 	"io"
+	"strconv"
 	"strings"
 
 	"github.com/go-viper/mapstructure/v2"
@@ -22,6 +24,8 @@ const (
 )
 
 // Parser for parse properties contents
+//
+// NOTE: Parser is not safe for concurrent use, use Store for share the config between goroutines.
 type Parser struct {
 	maputil.Data
 	// last parse error
@@ -33,6 +37,10 @@ type Parser struct {
 	smap maputil.SMap
 	// comments map
 	comments map[string]string
+	// entries metadata map
+	entries map[string]*Entry
+	// entries that are overridden by the same key later in the source
+	overridden []*Entry
 }
 
 // NewParser instance
@@ -43,6 +51,7 @@ func NewParser(optFns ...OpFunc) *Parser {
 		Data: make(maputil.Data),
 		// comments map
 		comments: make(map[string]string),
+		entries:  make(map[string]*Entry),
 	}
 
 	return p.WithOptions(optFns...)
@@ -66,7 +75,8 @@ func (p *Parser) Unmarshal(v []byte, ptr any) error {
 
 // Parse text contents
 func (p *Parser) Parse(text string) error {
-	if text = strings.TrimSpace(text); text == "" {
+	// NOTE: not trim the text, keep line number is correct
+	if strings.TrimSpace(text) == "" {
 		return errors.New("cannot input empty contents to parse")
 	}
 	return p.ParseFrom(strings.NewReader(text))
@@ -82,14 +92,24 @@ func (p *Parser) ParseBytes(bs []byte) error {
 
 // ParseFrom contents
 func (p *Parser) ParseFrom(r io.Reader) error {
+	// clear the error of last parse
+	p.err = nil
+	// only keep the overridden entries of this parse
+	p.overridden = nil
+
 	ts := textscan.NewScanner(r)
 	ts.AddMatchers(
 		&textscan.CommentsMatcher{
 			InlineChars: []byte{'#', '!'},
 		},
-		&textscan.KeyValueMatcher{
-			InlineComment: p.opts.InlineComment,
-			MergeComments: true,
+		&kvMatcher{
+			ts:      ts,
+			inline:  p.opts.InlineSlice,
+			bareKey: p.opts.BareKey,
+			KeyValueMatcher: textscan.KeyValueMatcher{
+				InlineComment: p.opts.InlineComment,
+				MergeComments: true,
+			},
 		},
 	)
 
@@ -99,16 +119,206 @@ func (p *Parser) ParseFrom(r io.Reader) error {
 
 		// collect value
 		if tok.Kind() == textscan.TokValue {
-			p.setValue(tok.(*textscan.ValueToken))
+			vt := tok.(*valueToken)
+			vt.endLine = ts.Line()
+			p.setValue(vt)
 		}
 	}
 
-	p.err = ts.Err()
+	if err := ts.Err(); err != nil {
+		p.err = err
+	}
+
+	if p.err == nil && p.opts.EnvPrefix != "" {
+		p.err = p.OverlayEnv(p.opts.EnvPrefix)
+	}
+
+	if p.err == nil && p.opts.InferType {
+		for key, val := range p.Data {
+			p.Data[key] = p.inferTypes(key, val)
+		}
+	}
 	return p.err
 }
 
+// inferTypes convert string values to typed values, quoted value will be skipped.
+func (p *Parser) inferTypes(path string, val any) any {
+	switch typVal := val.(type) {
+	case string:
+		if e, ok := p.entries[path]; ok && e.Quoted() {
+			return typVal
+		}
+		return inferValue(typVal)
+	case []string:
+		ls := make([]any, len(typVal))
+		for i, s := range typVal {
+			ls[i] = p.inferTypes(path+"["+strconv.Itoa(i)+"]", s)
+		}
+		return ls
+	case []any:
+		// inline value has been inferred on parse
+		if _, ok := p.entries[path]; ok {
+			return typVal
+		}
+		for i, v := range typVal {
+			typVal[i] = p.inferTypes(path+"["+strconv.Itoa(i)+"]", v)
+		}
+	case []map[string]any:
+		for i, mp := range typVal {
+			p.inferTypes(path+"["+strconv.Itoa(i)+"]", mp)
+		}
+	case map[string]any:
+		if _, ok := p.entries[path]; ok {
+			return typVal
+		}
+		for k, v := range typVal {
+			typVal[k] = p.inferTypes(path+"."+k, v)
+		}
+	}
+	return val
+}
+
+// kvMatcher wrap the textscan.KeyValueMatcher, collect more info for the value token.
+type kvMatcher struct {
+	textscan.KeyValueMatcher
+	// for get current line number
+	ts *textscan.TextScanner
+	// allow multi line inline value
+	inline bool
+	// allow the key only line
+	bareKey bool
+}
+
+// Match key-value line text.
+func (m *kvMatcher) Match(text string, prev textscan.Token) (textscan.Token, error) {
+	tok, err := m.KeyValueMatcher.Match(text, prev)
+	// key only line as empty value. eg: "key" -> "key="
+	if tok == nil && err == nil && m.bareKey && isBareKey(text) {
+		tok, err = m.KeyValueMatcher.Match(text+m.Separator, prev)
+	}
+	if err != nil || tok == nil {
+		return tok, err
+	}
+
+	vt := &valueToken{ValueToken: tok.(*textscan.ValueToken), line: m.ts.Line()}
+	if vt.Mark() == "" {
+		val := vt.ValueToken.Value()
+		vt.quote = detectQuote(text, val)
+
+		// multi line inline value. eg: "key = ["
+		if m.inline && vt.quote == QuoteNone && isInlineStart(val) {
+			vt.more = true
+			vt.inline = val
+		}
+	}
+	return vt, nil
+}
+
+// check the line is a key only line. eg: "key", "db.url"
+func isBareKey(text string) bool {
+	key := strings.TrimSpace(text)
+	return key != "" && !strings.ContainsAny(key, " \t")
+}
+
+// ErrInlineNotEnd error
+var ErrInlineNotEnd = errors.New("not end of multi line inline value")
+
+// valueToken with more info for the value
+type valueToken struct {
+	*textscan.ValueToken
+	// quote char of the value. is 0 on not quoted.
+	quote byte
+	// line number of the key, and end line of multi line value
+	line, endLine int
+	// multi line inline value
+	more   bool
+	inline string
+	// values of the continuation lines, the value ends with "\"
+	values []string
+}
+
+// Value text string.
+func (t *valueToken) Value() string {
+	if t.inline != "" {
+		return t.inline
+	}
+	return t.ValueToken.Value()
+}
+
+// HasMore is multi line value
+func (t *valueToken) HasMore() bool {
+	return t.more || t.ValueToken.HasMore()
+}
+
+// Values of the multi line value
+func (t *valueToken) Values() []string {
+	if t.values != nil {
+		return t.values
+	}
+	return t.ValueToken.Values()
+}
+
+// ScanMore scan multi line value
+func (t *valueToken) ScanMore(ts *textscan.TextScanner) error {
+	if t.Mark() == MultiLineValMarkQ {
+		return t.scanContinuation(ts)
+	}
+	if !t.more {
+		return t.ValueToken.ScanMore(ts)
+	}
+
+	for {
+		ok, line := ts.ScanNext()
+		if !ok {
+			return ErrInlineNotEnd
+		}
+
+		t.inline += "\n" + line
+		if inlineClosed(t.inline) {
+			return nil
+		}
+	}
+}
+
+// scan the continuation lines, the leading spaces of each line are ignored.
+func (t *valueToken) scanContinuation(ts *textscan.TextScanner) error {
+	t.values = t.ValueToken.Values()
+	for {
+		ok, line := ts.ScanNext()
+		if !ok {
+			return textscan.ErrMLineValueNotEnd
+		}
+
+		line = strings.TrimSpace(line)
+		if !strings.HasSuffix(line, MultiLineValMarkQ) {
+			t.values = append(t.values, line)
+			return nil
+		}
+		t.values = append(t.values, line[:len(line)-1])
+	}
+}
+
+// detect the raw value is quoted. val is the value after unquote.
+func detectQuote(text, val string) byte {
+	pos := strings.IndexByte(text, '=')
+	if pos < 0 {
+		return 0
+	}
+
+	raw := strings.TrimSpace(text[pos+1:])
+	if len(raw) < 2 || (raw[0] != '"' && raw[0] != '\'') {
+		return 0
+	}
+
+	q := raw[:1]
+	if strings.HasPrefix(raw, q+val+q) {
+		return raw[0]
+	}
+	return 0
+}
+
 // collect set value
-func (p *Parser) setValue(tok *textscan.ValueToken) {
+func (p *Parser) setValue(tok *valueToken) {
 	var value string
 	if tok.Mark() == textscan.MultiLineValMarkQ {
 		value = strings.Join(tok.Values(), "")
@@ -116,16 +326,40 @@ func (p *Parser) setValue(tok *textscan.ValueToken) {
 		value = tok.Value()
 	}
 
+	// process escape chars in double-quoted value
+	if tok.quote == QuoteDouble {
+		value = unescapeValue(value)
+	}
+
 	key := tok.Key()
+	entry := &Entry{Key: key, Quote: tok.quote, Line: tok.line, endLine: tok.endLine}
+	if old, ok := p.entries[key]; ok && old.Line > 0 {
+		p.overridden = append(p.overridden, old)
+	}
 	if tok.HasComment() {
-		p.comments[key] = tok.Comment()
+		entry.Comment = tok.Comment()
+		p.comments[key] = entry.Comment
 	}
+	p.entries[key] = entry
 
 	ln := len(value)
-	if p.opts.TrimValue && ln > 0 {
+	if p.opts.TrimValue && ln > 0 && tok.quote == QuoteNone {
 		value = strings.TrimSpace(value)
 	}
 
+	// decrypt the value. eg: ENC(base64...)
+	if p.opts.Decryptor != nil && IsEncrypted(value) {
+		plain, err := DecryptValue(p.opts.Decryptor, value)
+		if err != nil {
+			p.err = fmt.Errorf("decrypt the value of key %q: %w", key, err)
+			return
+		}
+
+		entry.cipher = value
+		value = plain
+		ln = len(value)
+	}
+
 	if p.opts.ParseVar && ln > 3 {
 		refName, ok := parseVarRefName(value)
 		if ok {
@@ -136,18 +370,26 @@ func (p *Parser) setValue(tok *textscan.ValueToken) {
 	var setVal any
 	setVal = value
 	p.smap[key] = value
+	entry.Value = value
 
 	if p.opts.ParseEnv && ln > 3 {
 		setVal = envutil.ParseEnvValue(value)
 	}
 
-	if p.opts.InlineSlice && ln > 2 {
-		ss, ok := parseInlineSlice(value, ln)
-		if ok {
-			setVal = ss
+	if p.opts.InlineSlice && ln > 1 && tok.quote == QuoteNone {
+		if val, ok := p.parseInline(value); ok {
+			setVal = val
 		}
 	}
 
+	if p.opts.BeforeCollect != nil {
+		setVal = p.opts.BeforeCollect(key, setVal)
+	}
+	p.collect(key, setVal)
+}
+
+// collect the value to Data by key path
+func (p *Parser) collect(key string, setVal any) {
 	var keys []string
 	if strings.ContainsRune(key, '.') {
 		keys = strings.Split(key, ".")
@@ -155,12 +397,14 @@ func (p *Parser) setValue(tok *textscan.ValueToken) {
 		keys = []string{key}
 	}
 
-	if p.opts.BeforeCollect != nil {
-		setVal = p.opts.BeforeCollect(key, setVal)
-	}
-
 	// set value by keys
-	if len(keys) == 1 {
+	if strings.ContainsRune(keys[0], '[') {
+		// top-level slice key. eg: "ids[0]", "users[0].name"
+		wrap := map[string]any{"": map[string]any(p.Data)}
+		if err := maputil.SetByKeys(&wrap, append([]string{""}, keys...), setVal); err != nil {
+			p.err = err
+		}
+	} else if len(keys) == 1 {
 		p.Data[key] = setVal
 	} else if len(p.Data) == 0 {
 		p.Data = maputil.MakeByKeys(keys, setVal)
@@ -172,6 +416,23 @@ func (p *Parser) setValue(tok *textscan.ValueToken) {
 	}
 }
 
+// parse inline list or map value. eg: [34, 56], {k: v}
+func (p *Parser) parseInline(s string) (any, bool) {
+	s = strings.TrimSpace(s)
+	ln := len(s)
+	if ln < 2 {
+		return nil, false
+	}
+
+	if (s[0] == '[' && s[ln-1] == ']') || (s[0] == '{' && s[ln-1] == '}') {
+		val, err := parseInlineValue(s, p.opts.InferType)
+		if err == nil {
+			return val, true
+		}
+	}
+	return nil, false
+}
+
 // ErrNotFound error
 var ErrNotFound = errors.New("this key does not exists")
 
@@ -182,12 +443,19 @@ func (p *Parser) Decode(ptr any) error {
 
 // MapStruct mapping data to a struct ptr
 func (p *Parser) MapStruct(key string, ptr any) error {
+	src := p.Data
+	// normalize keys for relaxed binding
+	if p.opts.RelaxedBinding {
+		src = relaxData(p.Data)
+		key = RelaxedKey(key)
+	}
+
 	var data any
 	if key == "" { // binding all data
-		data = p.Data
+		data = src
 	} else { // sub data of the p.Data
 		var ok bool
-		data, ok = p.Value(key)
+		data, ok = src.Value(key)
 		if !ok {
 			return ErrNotFound
 		}
@@ -200,6 +468,10 @@ func (p *Parser) MapStruct(key string, ptr any) error {
 	if err == nil {
 		err = decoder.Decode(data)
 	}
+
+	if err == nil && p.opts.Validator != nil {
+		err = p.validate(key, ptr)
+	}
 	return err
 }
 
@@ -212,3 +484,14 @@ func (p *Parser) SMap() maputil.SMap {
 func (p *Parser) Comments() map[string]string {
 	return p.comments
 }
+
+// Entry get metadata of the parsed key
+func (p *Parser) Entry(key string) (*Entry, bool) {
+	e, ok := p.entries[key]
+	return e, ok
+}
+
+// Entries metadata map of the parsed keys
+func (p *Parser) Entries() map[string]*Entry {
+	return p.entries
+}
@@ -235,6 +235,32 @@ key1 = val2
 	assert.NotEmpty(t, smp)
 	assert.ContainsKeys(t, smp, []string{"key0", "key1", "top.sub2.mline1"})
 	assert.Eq(t, "multi line value", smp.Str("top.sub2.mline1"))
+
+	// more continuation lines, leading spaces are ignored
+	text = `top.sub2.mline2 = this is \
+    multi line2 \
+  value
+key1 = val2
+`
+
+	p = properties.NewParser()
+	err = p.Parse(text)
+	assert.NoErr(t, err)
+	assert.Eq(t, "this is multi line2 value", p.SMap().Str("top.sub2.mline2"))
+	assert.Eq(t, "val2", p.SMap().Str("key1"))
+}
+
+func TestParser_Parse_bareKey(t *testing.T) {
+	p, err := properties.Parse("# comments\nempty\nname = inhere\n  db.url  \n", properties.ParseBareKey)
+	assert.NoErr(t, err)
+	assert.True(t, p.Has("empty"))
+	assert.Eq(t, "", p.Str("empty"))
+	assert.Eq(t, "", p.SMap()["db.url"])
+	assert.Eq(t, "inhere", p.Str("name"))
+	assert.Eq(t, "# comments", p.Comments()["empty"])
+
+	_, err = properties.Parse("invalid line", properties.ParseBareKey)
+	assert.ErrSubMsg(t, err, "no matcher available")
 }
 
 func TestParser_Parse_err(t *testing.T) {
@@ -251,6 +277,148 @@ func TestParser_Parse_err(t *testing.T) {
 	err = p.Parse("=value")
 	assert.ErrMsg(t, err, `key cannot be empty. line 1: "=value"`)
 
+	// error of last parse should be cleared
+	assert.NoErr(t, p.Parse("name = inhere"))
+	assert.Eq(t, "inhere", p.Str("name"))
+
 	err = p.Unmarshal(nil, nil)
 	assert.ErrMsg(t, err, `cannot input empty contents to parse`)
 }
+
+func TestParser_InferType(t *testing.T) {
+	text := `
+name = inhere
+age = 345
+score = 3.5
+enabled = true
+disabled = FALSE
+empty = null
+str-age = "123"
+zip = 0755
+top.sub.ids[0] = 23
+top.sub.ids[1] = abc
+top.sub.users[0].age = 12
+inline = [23, 4.5, false, "ab"]
+`
+
+	p := properties.NewParser(properties.InferType, properties.ParseInlineSlice)
+	err := p.Parse(text)
+	assert.NoErr(t, err)
+	dump.NoLoc(p.Data)
+
+	assert.Eq(t, "inhere", p.Get("name"))
+	assert.Eq(t, int64(345), p.Get("age"))
+	assert.Eq(t, 3.5, p.Get("score"))
+	assert.Eq(t, true, p.Get("enabled"))
+	assert.Eq(t, false, p.Get("disabled"))
+	assert.Nil(t, p.Get("empty"))
+	assert.True(t, p.Has("empty"))
+	assert.Eq(t, "123", p.Get("str-age"))
+	assert.Eq(t, "0755", p.Get("zip"))
+	assert.Eq(t, []any{int64(23), "abc"}, p.Get("top.sub.ids"))
+	assert.Eq(t, int64(12), p.Get("top.sub.users.0.age"))
+	assert.Eq(t, []any{int64(23), 4.5, false, "ab"}, p.Get("inline"))
+	// string map keep raw string
+	assert.Eq(t, "345", p.SMap().Get("age"))
+
+	// binding struct still work
+	cfg := &struct {
+		Age    int     `properties:"age"`
+		Score  float64 `properties:"score"`
+		StrAge int     `properties:"str-age"`
+	}{}
+	assert.NoErr(t, p.Decode(cfg))
+	assert.Eq(t, 345, cfg.Age)
+	assert.Eq(t, 3.5, cfg.Score)
+	assert.Eq(t, 123, cfg.StrAge)
+}
+
+func TestParser_Parse_quoteValue(t *testing.T) {
+	text := `
+key0 = a string value
+key1 = "a quote value1"
+key2 = 'a quote value2'
+key3 = "has \"escape\" chars\n\tand tab"
+key4 = 'no \n escape'
+key5 = "  has spaces  "
+`
+
+	p := properties.NewParser(func(opts *properties.Options) {
+		opts.TrimValue = true
+	})
+	err := p.Parse(text)
+	assert.NoErr(t, err)
+
+	e, ok := p.Entry("key0")
+	assert.True(t, ok)
+	assert.False(t, e.Quoted())
+	assert.Eq(t, "a string value", e.Value)
+
+	e, ok = p.Entry("key1")
+	assert.True(t, ok)
+	assert.True(t, e.Quoted())
+	assert.Eq(t, properties.QuoteDouble, e.Quote)
+	assert.Eq(t, "a quote value1", p.Str("key1"))
+
+	e, _ = p.Entry("key2")
+	assert.Eq(t, properties.QuoteSingle, e.Quote)
+	assert.Eq(t, "a quote value2", e.Value)
+
+	assert.Eq(t, "has \"escape\" chars\n\tand tab", p.Str("key3"))
+	assert.Eq(t, `no \n escape`, p.Str("key4"))
+	assert.Eq(t, "  has spaces  ", p.Str("key5"))
+	assert.Len(t, p.Entries(), 6)
+
+	_, ok = p.Entry("not-exists")
+	assert.False(t, ok)
+}
+
+func TestParser_Parse_inlineValue(t *testing.T) {
+	text := `
+list0 = []
+list1 = [a, "b, c", 'd]', e\,f]
+list2 = [[1, 2], [3], {k: v}]
+map0 = {}
+map1 = {name: inhere, "the key": [a, b], age = 23}
+mlist = [
+  abc,
+  "d, e",
+  [1, 2]
+]
+mmap = {
+  k1: v1,
+  k2: [a, b],
+}
+quoted = "[a, b]"
+invalid = [a, "b]
+key2 = val2
+`
+
+	p := properties.NewParser(properties.ParseInlineSlice)
+	err := p.Parse(text)
+	assert.NoErr(t, err)
+	dump.NoLoc(p.Data)
+
+	assert.Eq(t, []any{}, p.Get("list0"))
+	assert.Eq(t, []any{"a", "b, c", "d]", "e,f"}, p.Get("list1"))
+	assert.Eq(t, []any{[]any{"1", "2"}, []any{"3"}, map[string]any{"k": "v"}}, p.Get("list2"))
+	assert.Eq(t, map[string]any{}, p.Get("map0"))
+	assert.Eq(t, "inhere", p.Str("map1.name"))
+	assert.Eq(t, []any{"a", "b"}, p.Get("map1.the key"))
+	assert.Eq(t, "23", p.Str("map1.age"))
+	assert.Eq(t, []any{"abc", "d, e", []any{"1", "2"}}, p.Get("mlist"))
+	assert.Eq(t, []string{"a", "b"}, p.Strings("mmap.k2"))
+	assert.Eq(t, "[a, b]", p.Str("quoted"))
+	assert.Eq(t, `[a, "b]`, p.Str("invalid"))
+	assert.Eq(t, "val2", p.Str("key2"))
+
+	// with infer type
+	p = properties.NewParser(properties.ParseInlineSlice, properties.InferType)
+	assert.NoErr(t, p.Parse(`list = [1, "2", true, {k: 3.5, s: "4"}]`))
+	assert.Eq(t, []any{int64(1), "2", true, map[string]any{"k": 3.5, "s": "4"}}, p.Get("list"))
+
+	// not end
+	p = properties.NewParser(properties.ParseInlineSlice)
+	err = p.Parse("list = [\n  a,\n  b\n")
+	assert.ErrSubMsg(t, err, "not end of multi line inline value")
+}
@@ -0,0 +1,125 @@
+package properties
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// RelaxedName normalize the key name for relaxed binding, like Spring Boot.
+//
+// Will remove "-", "_" and convert to lower case. eg:
+//
+//	max-wait, maxWait, max_wait, MAX_WAIT -> maxwait
+func RelaxedName(name string) string {
+	var sb strings.Builder
+	sb.Grow(len(name))
+
+	for _, r := range name {
+		if r == '-' || r == '_' {
+			continue
+		}
+		sb.WriteRune(unicode.ToLower(r))
+	}
+	return sb.String()
+}
+
+// relaxedMatchName for mapstructure.DecoderConfig.MatchName
+func relaxedMatchName(mapKey, fieldName string) bool {
+	return RelaxedName(mapKey) == RelaxedName(fieldName)
+}
+
+// RelaxedKey normalize the key path for relaxed binding.
+//
+// eg: spring.redis.max-wait, SPRING_REDIS_MAXWAIT -> spring.redis.maxwait
+func RelaxedKey(key string) string {
+	if isEnvStyleKey(key) {
+		return strings.ToLower(strings.ReplaceAll(strings.Trim(key, "_"), "_", "."))
+	}
+
+	nodes := strings.Split(key, ".")
+	for i, node := range nodes {
+		nodes[i] = RelaxedName(node)
+	}
+	return strings.Join(nodes, ".")
+}
+
+// check is env var style key. eg: SPRING_REDIS_MAXWAIT
+func isEnvStyleKey(key string) bool {
+	if !strings.ContainsRune(key, '_') {
+		return false
+	}
+
+	for _, r := range key {
+		if r != '_' && !unicode.IsUpper(r) && !unicode.IsDigit(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// relaxData normalize all keys in data for relaxed binding, returns a new map.
+//
+// Env var style key will be split as nested keys. eg: SPRING_REDIS_HOST -> spring.redis.host
+func relaxData(data map[string]any) map[string]any {
+	keys := make([]string, 0, len(data))
+	for key := range data {
+		keys = append(keys, key)
+	}
+	// make merge order is stable
+	sort.Strings(keys)
+
+	mp := make(map[string]any, len(data))
+	for _, key := range keys {
+		val := relaxValue(data[key])
+		if isEnvStyleKey(key) {
+			setRelaxed(mp, strings.Split(RelaxedKey(key), "."), val)
+		} else {
+			setRelaxed(mp, []string{RelaxedName(key)}, val)
+		}
+	}
+	return mp
+}
+
+func relaxValue(val any) any {
+	switch typVal := val.(type) {
+	case map[string]any:
+		return relaxData(typVal)
+	case []map[string]any:
+		ls := make([]map[string]any, len(typVal))
+		for i, mp := range typVal {
+			ls[i] = relaxData(mp)
+		}
+		return ls
+	case []any:
+		ls := make([]any, len(typVal))
+		for i, v := range typVal {
+			ls[i] = relaxValue(v)
+		}
+		return ls
+	}
+	return val
+}
+
+// set value to the map by keys, will merge map value on key exists.
+func setRelaxed(mp map[string]any, keys []string, val any) {
+	key := keys[0]
+	if len(keys) > 1 {
+		sub, ok := mp[key].(map[string]any)
+		if !ok {
+			sub = make(map[string]any)
+			mp[key] = sub
+		}
+		setRelaxed(sub, keys[1:], val)
+		return
+	}
+
+	newMp, ok := val.(map[string]any)
+	if old, isMap := mp[key].(map[string]any); ok && isMap {
+		for k, v := range newMp {
+			setRelaxed(old, []string{k}, v)
+		}
+		return
+	}
+	mp[key] = val
+}
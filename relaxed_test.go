@@ -0,0 +1,90 @@
+package properties_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gookit/goutil/testutil/assert"
+	"github.com/gookit/properties"
+)
+
+func TestRelaxedKey(t *testing.T) {
+	assert.Eq(t, "maxwait", properties.RelaxedName("max-wait"))
+	assert.Eq(t, "maxwait", properties.RelaxedName("maxWait"))
+	assert.Eq(t, "maxwait", properties.RelaxedName("max_wait"))
+	assert.Eq(t, "maxwait", properties.RelaxedName("MAX_WAIT"))
+
+	assert.Eq(t, "spring.redis.maxwait", properties.RelaxedKey("spring.redis.max-wait"))
+	assert.Eq(t, "spring.redis.maxwait", properties.RelaxedKey("spring.Redis.maxWait"))
+	assert.Eq(t, "spring.redis.maxwait", properties.RelaxedKey("SPRING_REDIS_MAXWAIT"))
+}
+
+func TestOptions_RelaxedBinding(t *testing.T) {
+	type Pool struct {
+		MaxWait time.Duration
+		MinIdle int    `properties:"min-idle"`
+		MaxIdle int    `properties:"max_idle"`
+		Name    string `properties:"poolName"`
+	}
+
+	type Redis struct {
+		Host     string
+		Port     int
+		Password string
+		Pool     Pool `properties:"lettuce-pool"`
+	}
+
+	texts := []string{
+		`
+spring.redis.host = 127.0.0.1
+spring.redis.port = 6379
+spring.redis.lettuce-pool.max-wait = 3s
+spring.redis.lettuce-pool.min-idle = 2
+spring.redis.lettuce-pool.max-idle = 8
+spring.redis.lettuce-pool.pool-name = main
+`,
+		`
+spring.redis.host = 127.0.0.1
+spring.redis.port = 6379
+spring.redis.lettucePool.maxWait = 3s
+spring.redis.lettucePool.minIdle = 2
+spring.redis.lettucePool.maxIdle = 8
+spring.redis.lettucePool.poolName = main
+`,
+		`
+spring.redis.host = 127.0.0.1
+spring.redis.port = 6379
+spring.redis.lettuce_pool.max_wait = 3s
+spring.redis.lettuce_pool.min_idle = 2
+spring.redis.lettuce_pool.max_idle = 8
+spring.redis.lettuce_pool.pool_name = main
+`,
+		`
+SPRING_REDIS_HOST = 127.0.0.1
+SPRING_REDIS_PORT = 6379
+SPRING_REDIS_LETTUCEPOOL_MAXWAIT = 3s
+SPRING_REDIS_LETTUCEPOOL_MINIDLE = 2
+SPRING_REDIS_LETTUCEPOOL_MAXIDLE = 8
+SPRING_REDIS_LETTUCEPOOL_POOLNAME = main
+`,
+	}
+
+	for _, text := range texts {
+		p := properties.NewParser(properties.RelaxedBinding)
+		assert.NoErr(t, p.Parse(text))
+
+		cfg := &Redis{}
+		assert.NoErr(t, p.MapStruct("spring.redis", cfg))
+		assert.Eq(t, "127.0.0.1", cfg.Host)
+		assert.Eq(t, 6379, cfg.Port)
+		assert.Eq(t, 3*time.Second, cfg.Pool.MaxWait)
+		assert.Eq(t, 2, cfg.Pool.MinIdle)
+		assert.Eq(t, 8, cfg.Pool.MaxIdle)
+		assert.Eq(t, "main", cfg.Pool.Name)
+	}
+
+	// not found
+	p := properties.NewParser(properties.RelaxedBinding)
+	assert.NoErr(t, p.Parse(texts[0]))
+	assert.ErrIs(t, p.MapStruct("spring.not-exists", &Redis{}), properties.ErrNotFound)
+}
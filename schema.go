@@ -0,0 +1,617 @@
+package properties
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gookit/goutil/strutil"
+)
+
+// value types for the SchemaKey.Type
+const (
+	TypeString   = "string"
+	TypeInt      = "int"
+	TypeFloat    = "float"
+	TypeBool     = "bool"
+	TypeDuration = "duration"
+	// TypeList comma separated, inline or indexed list value. eg: "a,b", "[a, b]", "key[0]=a"
+	TypeList = "list"
+)
+
+// tag names for derive the schema from a struct. see SchemaFromStruct()
+var (
+	// ValidateTagName for validate rules. eg: `validate:"required,min=1,max=65535,oneof=tcp udp"`
+	ValidateTagName = "validate"
+	// DefaultValTagName for the default value. eg: `default:"8080"`
+	DefaultValTagName = "default"
+	// DescTagName for the description. eg: `desc:"listen port"`
+	DescTagName = "desc"
+)
+
+// KeyError a validation error of the key
+type KeyError struct {
+	// Key path name. eg: server.port
+	Key string
+	// Line number of the key in the source, is 0 on the key not exists.
+	Line int
+	// Message of the error
+	Message string
+}
+
+// Error string. eg: `line 3: key "server.port": must be <= 65535`
+func (e *KeyError) Error() string {
+	if e.Line > 0 {
+		return fmt.Sprintf("line %d: key %q: %s", e.Line, e.Key, e.Message)
+	}
+	return fmt.Sprintf("key %q: %s", e.Key, e.Message)
+}
+
+// KeyErrors multi validation errors
+type KeyErrors []*KeyError
+
+// Error string, one error per line.
+func (es KeyErrors) Error() string {
+	ss := make([]string, len(es))
+	for i, e := range es {
+		ss[i] = e.Error()
+	}
+	return strings.Join(ss, "\n")
+}
+
+// SchemaKey definition of a key in the Schema
+type SchemaKey struct {
+	// Key name or pattern, "*" match a key node or an index. eg: "db.*.host", "servers[*].port"
+	Key string `json:"key"`
+	// Type of the value, allow: string, int, float, bool, duration, list. default: string
+	Type string `json:"type,omitempty"`
+	// Required the key must be exists and not empty, pattern key must be matched once at least.
+	Required bool `json:"required,omitempty"`
+	// Enum allowed values
+	Enum []string `json:"enum,omitempty"`
+	// Min, Max range of the number value, or the length of string and list value.
+	Min *float64 `json:"min,omitempty"`
+	Max *float64 `json:"max,omitempty"`
+	// Pattern regexp for check the value string
+	Pattern string `json:"pattern,omitempty"`
+	// Default value of the key, a missing required key with default value is allowed.
+	Default string `json:"default,omitempty"`
+	// Description of the key, will be as comments on generate example.
+	Description string `json:"description,omitempty"`
+
+	keyRe *regexp.Regexp
+	valRe *regexp.Regexp
+}
+
+// WithRange set the min and max range, returns self.
+func (sk *SchemaKey) WithRange(min, max float64) *SchemaKey {
+	sk.Min, sk.Max = &min, &max
+	return sk
+}
+
+func (sk *SchemaKey) compile() (err error) {
+	switch sk.Type {
+	case "":
+		sk.Type = TypeString
+	case TypeString, TypeInt, TypeFloat, TypeBool, TypeDuration, TypeList:
+	default:
+		return fmt.Errorf("schema: invalid type %q of the key %q", sk.Type, sk.Key)
+	}
+
+	if sk.Key == "" {
+		return errors.New("schema: the key name cannot be empty")
+	}
+
+	// eg: "servers[*].port" -> `^servers\[[^.\[\]]+]\.port$`
+	expr := strings.ReplaceAll(regexp.QuoteMeta(sk.Key), `\*`, `[^.\[\]]+`)
+	if sk.Type == TypeList {
+		expr += `(\[\d+])?`
+	}
+	sk.keyRe = regexp.MustCompile("^" + expr + "$")
+
+	if sk.Pattern != "" {
+		if sk.valRe, err = regexp.Compile(sk.Pattern); err != nil {
+			return fmt.Errorf("schema: invalid pattern of the key %q: %w", sk.Key, err)
+		}
+	}
+	return nil
+}
+
+func (sk *SchemaKey) isPattern() bool {
+	return strings.ContainsRune(sk.Key, '*')
+}
+
+// check the value string, returns error message.
+func (sk *SchemaKey) checkValue(val string) string {
+	if len(sk.Enum) > 0 && !strutil.InArray(val, sk.Enum) {
+		return fmt.Sprintf("value %q must be one of [%s]", val, strings.Join(sk.Enum, ", "))
+	}
+	if sk.valRe != nil && !sk.valRe.MatchString(val) {
+		return fmt.Sprintf("value %q must match the pattern %s", val, sk.Pattern)
+	}
+
+	var num float64
+	var err error
+	switch sk.Type {
+	case TypeInt:
+		var iv int64
+		if iv, err = strconv.ParseInt(val, 10, 64); err != nil {
+			return fmt.Sprintf("value %q must be an integer", val)
+		}
+		num = float64(iv)
+	case TypeFloat:
+		if num, err = strconv.ParseFloat(val, 64); err != nil {
+			return fmt.Sprintf("value %q must be a float number", val)
+		}
+	case TypeBool:
+		if _, err = strconv.ParseBool(val); err != nil {
+			return fmt.Sprintf("value %q must be a bool", val)
+		}
+		return ""
+	case TypeDuration:
+		if _, err = time.ParseDuration(val); err != nil {
+			return fmt.Sprintf("value %q must be a duration. eg: 3s, 1h30m", val)
+		}
+		return ""
+	case TypeList: // check by the item count
+		return ""
+	default:
+		return sk.checkRange(float64(utf8.RuneCountInString(val)), "length")
+	}
+	return sk.checkRange(num, "value")
+}
+
+func (sk *SchemaKey) checkRange(num float64, name string) string {
+	if sk.Min != nil && num < *sk.Min {
+		return fmt.Sprintf("%s must be >= %v", name, *sk.Min)
+	}
+	if sk.Max != nil && num > *sk.Max {
+		return fmt.Sprintf("%s must be <= %v", name, *sk.Max)
+	}
+	return ""
+}
+
+// Schema for validate the parsed properties, can be defined in Go or a JSON file.
+//
+// JSON example:
+//
+//	{
+//	  "strict": true,
+//	  "keys": [
+//	    {"key": "server.port", "type": "int", "required": true, "min": 1, "max": 65535},
+//	    {"key": "log.level", "enum": ["debug", "info", "error"], "default": "info"}
+//	  ]
+//	}
+type Schema struct {
+	// Keys definitions
+	Keys []*SchemaKey `json:"keys"`
+	// Strict report the keys not defined in the schema. default: false
+	Strict bool `json:"strict,omitempty"`
+}
+
+// NewSchema instance
+func NewSchema(keys ...*SchemaKey) *Schema {
+	return &Schema{Keys: keys}
+}
+
+// ParseSchema parse the JSON schema contents
+func ParseSchema(bs []byte) (*Schema, error) {
+	s := &Schema{}
+	if err := json.Unmarshal(bs, s); err != nil {
+		return nil, err
+	}
+	return s, s.Compile()
+}
+
+// Add key definitions
+func (s *Schema) Add(keys ...*SchemaKey) *Schema {
+	s.Keys = append(s.Keys, keys...)
+	return s
+}
+
+// Compile check the definitions and compile the patterns.
+func (s *Schema) Compile() error {
+	for _, sk := range s.Keys {
+		if err := sk.compile(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Validate the parsed data, returns KeyErrors on has violations.
+func (s *Schema) Validate(p *Parser) error {
+	if err := s.Compile(); err != nil {
+		return err
+	}
+
+	keys := p.smap.Keys()
+	sort.Strings(keys)
+
+	var es KeyErrors
+	addErr := func(key, msg string) {
+		es = append(es, &KeyError{Key: key, Line: p.lineOf(key), Message: msg})
+	}
+
+	known := make(map[string]bool, len(keys))
+	for _, sk := range s.Keys {
+		var matched int
+		// item counts and first key of the list value
+		items := make(map[string]int)
+		firsts := make(map[string]string)
+
+		for _, key := range keys {
+			if !sk.keyRe.MatchString(key) {
+				continue
+			}
+
+			matched++
+			known[key] = true
+			val := p.smap[key]
+
+			if sk.Type == TypeList {
+				base := key
+				if pos := strings.LastIndexByte(key, '['); pos > 0 && strings.HasSuffix(key, "]") {
+					base = key[:pos]
+					items[base]++
+				} else {
+					items[base] += len(listItems(val))
+				}
+				if _, ok := firsts[base]; !ok {
+					firsts[base] = key
+				}
+
+				if val != "" {
+					if msg := sk.checkValue(val); msg != "" {
+						addErr(key, msg)
+					}
+				}
+				continue
+			}
+
+			if val == "" {
+				if sk.Required {
+					addErr(key, "required value is empty")
+				}
+				continue
+			}
+
+			if msg := sk.checkValue(val); msg != "" {
+				addErr(key, msg)
+			}
+		}
+
+		if matched == 0 && sk.Required && sk.Default == "" {
+			addErr(sk.Key, "required key is missing")
+		}
+
+		for base, n := range items {
+			msg := sk.checkRange(float64(n), "item count")
+			if msg == "" && n == 0 && sk.Required {
+				msg = "required value is empty"
+			}
+			if msg != "" {
+				es = append(es, &KeyError{Key: base, Line: p.lineOf(firsts[base]), Message: msg})
+			}
+		}
+	}
+
+	if s.Strict {
+		for _, key := range keys {
+			if !known[key] {
+				addErr(key, "unknown key, it is not defined in the schema")
+			}
+		}
+	}
+
+	if len(es) == 0 {
+		return nil
+	}
+
+	sort.SliceStable(es, func(i, j int) bool {
+		if es[i].Line == es[j].Line {
+			return es[i].Key < es[j].Key
+		}
+		return es[i].Line < es[j].Line
+	})
+	return es
+}
+
+// ApplyDefaults set the default values to the Parser for the missing keys. pattern keys will be skipped.
+func (s *Schema) ApplyDefaults(p *Parser) error {
+	for _, sk := range s.Keys {
+		if sk.Default == "" || sk.isPattern() {
+			continue
+		}
+
+		if _, ok := p.smap[sk.Key]; !ok {
+			p.overlayValue(sk.Key, sk.Default)
+		}
+	}
+	return p.err
+}
+
+// Example generate a documented example properties contents by the schema.
+//
+// The value is the default value or first enum value, pattern key "*" will be replaced. eg:
+//
+//	# Server listen port
+//	# type: int, required, min: 1, max: 65535
+//	server.port = 8080
+func (s *Schema) Example() []byte {
+	var buf bytes.Buffer
+	for i, sk := range s.Keys {
+		if i > 0 {
+			buf.WriteByte('\n')
+		}
+
+		if sk.Description != "" {
+			for _, line := range strings.Split(sk.Description, "\n") {
+				buf.WriteString("# " + line + "\n")
+			}
+		}
+		buf.WriteString("# " + sk.attrs() + "\n")
+
+		key := strings.ReplaceAll(strings.ReplaceAll(sk.Key, "[*]", "[0]"), "*", "name")
+		val := sk.Default
+		if val == "" && len(sk.Enum) > 0 {
+			val = sk.Enum[0]
+		}
+
+		if val == "" {
+			buf.WriteString(key + " =\n")
+		} else {
+			buf.WriteString(key + " = " + formatValue(val, QuoteNone) + "\n")
+		}
+	}
+	return buf.Bytes()
+}
+
+// attributes description of the key. eg: "type: int, required, min: 1"
+func (sk *SchemaKey) attrs() string {
+	typ := sk.Type
+	if typ == "" {
+		typ = TypeString
+	}
+
+	ss := []string{"type: " + typ}
+	if sk.Required {
+		ss = append(ss, "required")
+	}
+	if len(sk.Enum) > 0 {
+		ss = append(ss, "enum: "+strings.Join(sk.Enum, "|"))
+	}
+	if sk.Min != nil {
+		ss = append(ss, fmt.Sprintf("min: %v", *sk.Min))
+	}
+	if sk.Max != nil {
+		ss = append(ss, fmt.Sprintf("max: %v", *sk.Max))
+	}
+	if sk.Pattern != "" {
+		ss = append(ss, "pattern: "+sk.Pattern)
+	}
+	return strings.Join(ss, ", ")
+}
+
+// list items of the list value. eg: "a,b", "[a, b]"
+func listItems(val string) []string {
+	val = strings.TrimSpace(val)
+	if val == "" {
+		return nil
+	}
+
+	if len(val) > 1 && val[0] == '[' && val[len(val)-1] == ']' {
+		if ls, err := parseInlineValue(val, false); err == nil {
+			if items, ok := ls.([]any); ok {
+				ss := make([]string, len(items))
+				for i, item := range items {
+					ss[i] = scalarString(item)
+				}
+				return ss
+			}
+		}
+	}
+	return strutil.SplitTrimmed(val, ",")
+}
+
+// SchemaFromStruct derive a schema from the tagged struct.
+//
+// The key name use the struct tag(default: properties) or the NameStrategy of options,
+// otherwise use the lower case field name, same as the flags of BindFlags. And read more tags:
+//
+//	validate: rules of the value. allow: required, min=N, max=N, oneof=a b c, regex=^\w+$
+//	default: default value of the key
+//	desc: description of the key
+//
+// NOTE: regex rule should not contain the comma.
+func SchemaFromStruct(ptr any, optFns ...OpFunc) (*Schema, error) {
+	rt := reflect.TypeOf(ptr)
+	for rt != nil && rt.Kind() == reflect.Ptr {
+		rt = rt.Elem()
+	}
+	if rt == nil || rt.Kind() != reflect.Struct {
+		return nil, errors.New("must be provide a struct or struct ptr for derive the schema")
+	}
+
+	opts := newDefaultOption()
+	for _, fn := range optFns {
+		fn(opts)
+	}
+
+	s := &Schema{}
+	if err := s.addStruct(rt, "", opts); err != nil {
+		return nil, err
+	}
+	return s, s.Compile()
+}
+
+func (s *Schema) addStruct(rt reflect.Type, parent string, opts *Options) error {
+	tagName := opts.tagName()
+	for i := 0; i < rt.NumField(); i++ {
+		sf := rt.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+
+		name, tagOpts, _ := strings.Cut(sf.Tag.Get(tagName), ",")
+		if name == "-" {
+			continue
+		}
+
+		ft := sf.Type
+		for ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+
+		if sf.Anonymous && strings.Contains(tagOpts, "squash") {
+			if isNestedType(ft) {
+				if err := s.addStruct(ft, parent, opts); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		// untagged field, use the name strategy or lower case. eg: "Host" -> "host"
+		if name == "" {
+			if opts.NameStrategy != nil {
+				name = opts.NameStrategy(sf.Name)
+			} else {
+				name = strings.ToLower(sf.Name)
+			}
+		}
+
+		key := name
+		if parent != "" {
+			key = parent + "." + name
+		}
+
+		sk, err := schemaKeyOf(sf)
+		if err != nil {
+			return err
+		}
+
+		switch ft.Kind() {
+		case reflect.Struct:
+			if isNestedType(ft) {
+				if err := s.addStruct(ft, key, opts); err != nil {
+					return err
+				}
+				continue
+			}
+		case reflect.Slice, reflect.Array:
+			elem := ft.Elem()
+			for elem.Kind() == reflect.Ptr {
+				elem = elem.Elem()
+			}
+			if isNestedType(elem) {
+				if err := s.addStruct(elem, key+"[*]", opts); err != nil {
+					return err
+				}
+				continue
+			}
+			if ft.Elem().Kind() != reflect.Uint8 {
+				sk.Type = TypeList
+			}
+		case reflect.Map:
+			elem := ft.Elem()
+			if isNestedType(elem) {
+				if err := s.addStruct(elem, key+".*", opts); err != nil {
+					return err
+				}
+				continue
+			}
+			key += ".*"
+			sk.Type = schemaType(elem)
+		default:
+			sk.Type = schemaType(ft)
+		}
+
+		sk.Key = key
+		s.Keys = append(s.Keys, sk)
+	}
+	return nil
+}
+
+// schemaKeyOf create the SchemaKey by field tags, the Key and Type are not set.
+func schemaKeyOf(sf reflect.StructField) (*SchemaKey, error) {
+	sk := &SchemaKey{
+		Default:     sf.Tag.Get(DefaultValTagName),
+		Description: sf.Tag.Get(DescTagName),
+	}
+
+	for _, rule := range parseRuleTag(sf.Tag.Get(ValidateTagName)) {
+		switch rule.name {
+		case "required":
+			sk.Required = true
+		case "min", "max":
+			num, err := strconv.ParseFloat(rule.arg, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s rule of the field %s: %w", rule.name, sf.Name, err)
+			}
+			if rule.name == "min" {
+				sk.Min = &num
+			} else {
+				sk.Max = &num
+			}
+		case "oneof":
+			sk.Enum = strings.Fields(rule.arg)
+		case "regex":
+			sk.Pattern = rule.arg
+		}
+	}
+	return sk, nil
+}
+
+// schemaType of the go type
+func schemaType(rt reflect.Type) string {
+	for rt.Kind() == reflect.Ptr {
+		rt = rt.Elem()
+	}
+
+	if rt == durationType {
+		return TypeDuration
+	}
+
+	switch rt.Kind() {
+	case reflect.Bool:
+		return TypeBool
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return TypeInt
+	case reflect.Float32, reflect.Float64:
+		return TypeFloat
+	case reflect.Slice, reflect.Array:
+		return TypeList
+	}
+	return TypeString
+}
+
+// tagRule a rule of the validate tag. eg: "min=1" -> {name: "min", arg: "1"}
+type tagRule struct {
+	name, arg string
+}
+
+// parse the validate tag. eg: "required,min=1,max=65535"
+func parseRuleTag(tag string) []tagRule {
+	if tag == "" || tag == "-" {
+		return nil
+	}
+
+	var rules []tagRule
+	for _, str := range strings.Split(tag, ",") {
+		name, arg, _ := strings.Cut(strings.TrimSpace(str), "=")
+		if name != "" {
+			rules = append(rules, tagRule{name: name, arg: arg})
+		}
+	}
+	return rules
+}
@@ -0,0 +1,197 @@
+package properties_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gookit/goutil/testutil/assert"
+	"github.com/gookit/properties"
+)
+
+var schemaJSON = `{
+  "strict": true,
+  "keys": [
+    {"key": "server.port", "type": "int", "required": true, "min": 1, "max": 65535, "description": "listen port"},
+    {"key": "server.host", "default": "localhost"},
+    {"key": "log.level", "enum": ["debug", "info", "error"], "default": "info"},
+    {"key": "app.name", "required": true, "pattern": "^[a-z]+$", "max": 8},
+    {"key": "app.timeout", "type": "duration"},
+    {"key": "app.debug", "type": "bool"},
+    {"key": "db.*.rate", "type": "float", "max": 1},
+    {"key": "app.tags", "type": "list", "min": 2}
+  ]
+}`
+
+func TestSchema_Validate(t *testing.T) {
+	s, err := properties.ParseSchema([]byte(schemaJSON))
+	assert.NoErr(t, err)
+
+	p := properties.NewParser()
+	err = p.Parse(`
+server.port = 8080
+app.name = myapp
+app.timeout = 3s
+app.debug = true
+db.master.rate = 0.5
+db.slave.rate = 0.2
+app.tags = a, b
+`)
+	assert.NoErr(t, err)
+	assert.NoErr(t, s.Validate(p))
+
+	p = properties.NewParser()
+	err = p.Parse(`server.port = 70000
+app.name = MyApp
+app.timeout = 3
+app.debug = yes
+log.level = warn
+db.master.rate = 1.5
+app.tags[0] = a
+unknown = value
+`)
+	assert.NoErr(t, err)
+
+	err = s.Validate(p)
+	assert.Err(t, err)
+
+	var es properties.KeyErrors
+	assert.True(t, errors.As(err, &es))
+	assert.Len(t, es, 8)
+	assert.Eq(t, `line 1: key "server.port": value must be <= 65535`, es[0].Error())
+	assert.Eq(t, `line 7: key "app.tags": item count must be >= 2`, es[6].Error())
+
+	msgs := make(map[string]string)
+	for _, e := range es {
+		msgs[e.Key] = e.Message
+	}
+
+	assert.Eq(t, `value "MyApp" must match the pattern ^[a-z]+$`, msgs["app.name"])
+	assert.StrContains(t, msgs["app.timeout"], "must be a duration")
+	assert.Eq(t, `value "yes" must be a bool`, msgs["app.debug"])
+	assert.Eq(t, `value "warn" must be one of [debug, info, error]`, msgs["log.level"])
+	assert.Eq(t, "value must be <= 1", msgs["db.master.rate"])
+	assert.Eq(t, "item count must be >= 2", msgs["app.tags"])
+	assert.Eq(t, "unknown key, it is not defined in the schema", msgs["unknown"])
+
+	// missing required key
+	p = properties.NewParser()
+	assert.NoErr(t, p.Parse("app.name = abc\n"))
+	err = s.Validate(p)
+	assert.Eq(t, `key "server.port": required key is missing`, err.Error())
+}
+
+func TestSchema_Go(t *testing.T) {
+	s := properties.NewSchema(
+		(&properties.SchemaKey{Key: "name", Required: true}).WithRange(2, 5),
+		&properties.SchemaKey{Key: "users[*].age", Type: properties.TypeInt},
+	)
+
+	p := properties.NewParser()
+	assert.NoErr(t, p.Parse("name = i\nusers[0].age = 23\nusers[1].age = abc\nname2 = a\n"))
+
+	err := s.Validate(p)
+	assert.Eq(t, `line 1: key "name": length must be >= 2
+line 3: key "users[1].age": value "abc" must be an integer`, err.Error())
+
+	assert.ErrSubMsg(t, properties.NewSchema(&properties.SchemaKey{Key: "a", Type: "object"}).Compile(), `invalid type "object"`)
+	assert.ErrSubMsg(t, properties.NewSchema(&properties.SchemaKey{Key: "a", Pattern: "[a"}).Compile(), "invalid pattern")
+
+	_, err = properties.ParseSchema([]byte(`{"keys": [{"key": ""}]}`))
+	assert.Err(t, err)
+}
+
+func TestSchema_Example_ApplyDefaults(t *testing.T) {
+	s, err := properties.ParseSchema([]byte(schemaJSON))
+	assert.NoErr(t, err)
+
+	bs := s.Example()
+	assert.StrContains(t, string(bs), `# listen port
+# type: int, required, min: 1, max: 65535
+server.port =
+
+# type: string
+server.host = localhost
+
+# type: string, enum: debug|info|error
+log.level = info
+`)
+	assert.StrContains(t, string(bs), "# type: float, max: 1\ndb.name.rate =\n")
+
+	p := properties.NewParser()
+	assert.NoErr(t, p.Parse("log.level = debug\n"))
+	assert.NoErr(t, s.ApplyDefaults(p))
+	assert.Eq(t, "localhost", p.Str("server.host"))
+	assert.Eq(t, "debug", p.Str("log.level"))
+}
+
+type schemaConf struct {
+	Name    string        `properties:"name" validate:"required,regex=^[a-z]+$" desc:"app name"`
+	Port    int           `properties:"port" validate:"min=1,max=65535" default:"8080"`
+	Mode    string        `properties:"mode" validate:"oneof=dev prod"`
+	Timeout time.Duration `properties:"timeout"`
+	Tags    []string      `properties:"tags"`
+	DB      struct {
+		Host string `properties:"host"`
+		Rate float64
+	} `properties:"db"`
+	Servers []struct {
+		Addr string `properties:"addr" validate:"required"`
+	} `properties:"servers"`
+	Labels map[string]string `properties:"labels"`
+	Ignore string            `properties:"-"`
+}
+
+func TestSchemaFromStruct(t *testing.T) {
+	s, err := properties.SchemaFromStruct(&schemaConf{}, properties.WithNameStrategy(properties.KebabCase))
+	assert.NoErr(t, err)
+
+	keys := make([]string, len(s.Keys))
+	for i, sk := range s.Keys {
+		keys[i] = sk.Key + ":" + sk.Type
+	}
+	assert.Eq(t, []string{
+		"name:string",
+		"port:int",
+		"mode:string",
+		"timeout:duration",
+		"tags:list",
+		"db.host:string",
+		"db.rate:float",
+		"servers[*].addr:string",
+		"labels.*:string",
+	}, keys)
+
+	assert.True(t, s.Keys[0].Required)
+	assert.Eq(t, "app name", s.Keys[0].Description)
+	assert.Eq(t, "8080", s.Keys[1].Default)
+	assert.Eq(t, float64(65535), *s.Keys[1].Max)
+	assert.Eq(t, []string{"dev", "prod"}, s.Keys[2].Enum)
+
+	p := properties.NewParser()
+	assert.NoErr(t, p.Parse("name = app\nmode = test\nservers[0].addr = 127.0.0.1\nlabels.env = dev\n"))
+	err = s.Validate(p)
+	assert.Eq(t, `line 2: key "mode": value "test" must be one of [dev, prod]`, err.Error())
+
+	// untagged struct, the key name is lower case
+	s, err = properties.SchemaFromStruct(&struct {
+		Server struct {
+			Host string `validate:"required"`
+			Port int    `validate:"max=65535"`
+		}
+	}{})
+	assert.NoErr(t, err)
+	assert.Eq(t, "server.host", s.Keys[0].Key)
+	assert.Eq(t, "server.port", s.Keys[1].Key)
+
+	p = properties.NewParser()
+	assert.NoErr(t, p.Parse("server.host = h\nserver.port = 70000\n"))
+	assert.Eq(t, `line 2: key "server.port": value must be <= 65535`, s.Validate(p).Error())
+
+	_, err = properties.SchemaFromStruct("invalid")
+	assert.Err(t, err)
+	_, err = properties.SchemaFromStruct(&struct {
+		Port int `validate:"min=abc"`
+	}{})
+	assert.ErrSubMsg(t, err, "invalid min rule of the field Port")
+}
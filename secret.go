@@ -0,0 +1,44 @@
+package properties
+
+import "strings"
+
+// MaskedValue for replace the secret value on output
+var MaskedValue = "******"
+
+// DefaultSecretKeys the key name contains one of these words will be as a secret key.
+//
+// NOTE: will ignore case and "-", "_" on match. eg: db.Password, api-key, ACCESS_KEY
+var DefaultSecretKeys = []string{
+	"password",
+	"passwd",
+	"pwd",
+	"secret",
+	"token",
+	"credential",
+	"apikey",
+	"privatekey",
+	"accesskey",
+}
+
+// IsSecretKey check the key is a secret key. will use DefaultSecretKeys on words is empty.
+func IsSecretKey(key string, words ...string) bool {
+	if len(words) == 0 {
+		words = DefaultSecretKeys
+	}
+
+	name := RelaxedName(key)
+	for _, word := range words {
+		if strings.Contains(name, RelaxedName(word)) {
+			return true
+		}
+	}
+	return false
+}
+
+// MaskValue mask the not empty value. eg: "abc" -> "******"
+func MaskValue(val string) string {
+	if val == "" {
+		return ""
+	}
+	return MaskedValue
+}
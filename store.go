@@ -0,0 +1,158 @@
+package properties
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/gookit/goutil/maputil"
+)
+
+// Store a concurrency-safe config holder, keep an immutable Parser snapshot.
+//
+// Reads are lock-free on the current snapshot. Updates are copy-on-write:
+// clone the current snapshot, modify the clone and swap it atomically. eg:
+//
+//	s := properties.NewStore(p)
+//	// in goroutines
+//	host := s.Str("db.host")
+//	err := s.Set("db.port", "3307")
+type Store struct {
+	current atomic.Pointer[Parser]
+	// serialize the writers
+	mu sync.Mutex
+}
+
+// NewStore instance. will use an empty Parser on p is nil.
+//
+// NOTE: the p should not be modified after create the store.
+func NewStore(p *Parser) *Store {
+	if p == nil {
+		p = NewParser()
+	}
+
+	s := &Store{}
+	s.current.Store(p)
+	return s
+}
+
+// Snapshot get the current config snapshot.
+//
+// NOTE: the snapshot is shared by all readers, should not modify the returned Parser,
+// and the Data, SMap(), Comments(), Entries() of it. use Update() for modify the config.
+func (s *Store) Snapshot() *Parser {
+	return s.current.Load()
+}
+
+// Swap the snapshot by new parser, returns the old snapshot. eg: reload the config
+func (s *Store) Swap(p *Parser) *Parser {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.current.Swap(p)
+}
+
+// Get value by key path. eg: top.sub.key
+//
+// NOTE: the map and slice value is shared with the snapshot, should not modify it.
+func (s *Store) Get(key string) (any, bool) {
+	return s.Snapshot().Value(key)
+}
+
+// Str get string value by key path
+func (s *Store) Str(key string, defVal ...string) string {
+	return s.Snapshot().Str(key, defVal...)
+}
+
+// Int get int value by key path
+func (s *Store) Int(key string, defVal ...int) int {
+	return s.Snapshot().Int(key, defVal...)
+}
+
+// Bool get bool value by key path
+func (s *Store) Bool(key string) bool {
+	return s.Snapshot().Bool(key)
+}
+
+// SMap get a copy of the string map data of the snapshot
+func (s *Store) SMap() maputil.SMap {
+	smp := s.Snapshot().SMap()
+	cp := make(maputil.SMap, len(smp))
+	for key, val := range smp {
+		cp[key] = val
+	}
+	return cp
+}
+
+// FlatKeys get the flat keys in file order. see Parser.FlatKeys
+func (s *Store) FlatKeys(prefix ...string) []string {
+	return s.Snapshot().FlatKeys(prefix...)
+}
+
+// MapStruct mapping the snapshot data to a struct ptr
+func (s *Store) MapStruct(key string, ptr any) error {
+	return s.Snapshot().MapStruct(key, ptr)
+}
+
+// Decode the snapshot data to struct ptr
+func (s *Store) Decode(ptr any) error {
+	return s.Snapshot().MapStruct("", ptr)
+}
+
+// Update the config by copy-on-write, fn will modify a clone of the current snapshot.
+//
+// The snapshot will not be swapped on fn returns error.
+func (s *Store) Update(fn func(p *Parser) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p := s.current.Load().Clone()
+	if err := fn(p); err != nil {
+		return err
+	}
+
+	s.current.Store(p)
+	return nil
+}
+
+// Set a raw string value to the key, will override the exists value.
+func (s *Store) Set(key, value string) error {
+	return s.Update(func(p *Parser) error {
+		p.overlayValue(key, value)
+		return p.err
+	})
+}
+
+// Merge the src data to the config. see Merge
+func (s *Store) Merge(src *Parser, strategy MergeStrategy) error {
+	return s.Update(func(p *Parser) error {
+		return Merge(p, src, strategy)
+	})
+}
+
+// Clone deep copy the parser, includes the Data, SMap, comments and entries.
+func (p *Parser) Clone() *Parser {
+	opts := *p.opts
+	np := &Parser{
+		opts:     &opts,
+		Data:     cloneValue(map[string]any(p.Data)).(map[string]any),
+		smap:     make(maputil.SMap, len(p.smap)),
+		comments: make(map[string]string, len(p.comments)),
+		entries:  make(map[string]*Entry, len(p.entries)),
+	}
+
+	for key, val := range p.smap {
+		np.smap[key] = val
+	}
+	for key, cmt := range p.comments {
+		np.comments[key] = cmt
+	}
+	for key, e := range p.entries {
+		ne := *e
+		np.entries[key] = &ne
+	}
+
+	if len(p.overridden) > 0 {
+		np.overridden = make([]*Entry, len(p.overridden))
+		copy(np.overridden, p.overridden)
+	}
+	return np
+}
@@ -0,0 +1,113 @@
+package properties_test
+
+import (
+	"errors"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/gookit/goutil/testutil/assert"
+	"github.com/gookit/properties"
+)
+
+func TestStore_basic(t *testing.T) {
+	p, err := properties.Parse("# db\ndb.host = localhost\ndb.port = 3306\ntags[0] = a\n")
+	assert.NoErr(t, err)
+
+	s := properties.NewStore(p)
+	assert.Eq(t, "localhost", s.Str("db.host"))
+	assert.Eq(t, 3306, s.Int("db.port"))
+	assert.Eq(t, []string{"db.host", "db.port", "tags[0]"}, s.FlatKeys())
+
+	// copy-on-write
+	old := s.Snapshot()
+	assert.NoErr(t, s.Set("db.port", "3307"))
+	assert.NoErr(t, s.Set("debug", "true"))
+	assert.Eq(t, 3307, s.Int("db.port"))
+	assert.True(t, s.Bool("debug"))
+	assert.Eq(t, "3306", old.Str("db.port"))
+	assert.False(t, old.Has("debug"))
+	assert.Eq(t, "3307", s.SMap()["db.port"])
+
+	// returns a copy of the SMap
+	smp := s.SMap()
+	smp["db.port"] = "1"
+	assert.Eq(t, "3307", s.Snapshot().SMap()["db.port"])
+
+	val, ok := s.Get("db.host")
+	assert.True(t, ok)
+	assert.Eq(t, "localhost", val)
+
+	src, err := properties.Parse("db.host = 127.0.0.1\nname = app\n")
+	assert.NoErr(t, err)
+	assert.NoErr(t, s.Merge(src, properties.MergeOverride))
+	assert.Eq(t, "127.0.0.1", s.Str("db.host"))
+	assert.Eq(t, "localhost", old.Str("db.host"))
+
+	var cfg struct {
+		Host string `properties:"host"`
+		Port int    `properties:"port"`
+	}
+	assert.NoErr(t, s.MapStruct("db", &cfg))
+	assert.Eq(t, 3307, cfg.Port)
+
+	// not swap on error
+	cur := s.Snapshot()
+	err = s.Update(func(p *properties.Parser) error {
+		p.Set("name", "other")
+		return errors.New("update error")
+	})
+	assert.Err(t, err)
+	assert.Eq(t, cur, s.Snapshot())
+	assert.Eq(t, "app", s.Str("name"))
+
+	// swap
+	np, err := properties.Parse("name = new\n")
+	assert.NoErr(t, err)
+	assert.Eq(t, cur, s.Swap(np))
+	assert.Eq(t, "new", s.Str("name"))
+	assert.NotNil(t, properties.NewStore(nil).Snapshot())
+}
+
+func TestStore_concurrent(t *testing.T) {
+	p, err := properties.Parse("server.port = 8080\nserver.host = localhost\n")
+	assert.NoErr(t, err)
+	s := properties.NewStore(p)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				_ = s.Set("server.port", strconv.Itoa(8000+j))
+				_ = s.Set("workers.w"+strconv.Itoa(i), strconv.Itoa(j))
+
+				src := properties.NewParser()
+				_ = src.Parse("server.host = host" + strconv.Itoa(j) + "\n")
+				_ = s.Merge(src, properties.MergeOverride)
+			}
+		}(i)
+
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				snap := s.Snapshot()
+				_ = snap.Str("server.host")
+				_ = snap.FlatKeys()
+				_ = s.Int("server.port")
+
+				var cfg struct{ Port int }
+				_ = s.MapStruct("server", &cfg)
+				for key := range snap.Data {
+					_ = snap.Data[key]
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Eq(t, "host49", s.Str("server.host"))
+	assert.Eq(t, 8049, s.Int("server.port"))
+	assert.Eq(t, "49", s.Str("workers.w3"))
+}
@@ -0,0 +1,160 @@
+package properties
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/gookit/goutil/maputil"
+)
+
+// Scope get a scoped parser of the sub-tree by the key prefix, the prefix will be removed from the keys.
+//
+// The Data, SMap, comments and entries are copied, modify it will not affect the parent. eg:
+//
+//	redis := p.Scope("spring.redis")
+//	redis.Str("host") // same as p.Str("spring.redis.host")
+//
+// NOTE: p.Sub(key) is the maputil.Data.Sub() for get the sub map data.
+func (p *Parser) Scope(prefix string) *Parser {
+	return p.rekey(func(key string) (string, bool) {
+		sub, ok := trimKeyPrefix(key, prefix)
+		// not allow slice index as the top key. eg: "tags[0]" -> "[0]"
+		return sub, ok && sub[0] != '['
+	})
+}
+
+// FlatKeys get the flat keys of the SMap in file order, only return the keys under the prefix on it's not empty.
+//
+// The keys not from the source are sorted by name at the end. eg: Set, OverlayEnv
+//
+// eg: FlatKeys("spring.redis") -> ["spring.redis.host", "spring.redis.port"]
+//
+// NOTE: p.Keys() is the top-level keys of the Data.
+func (p *Parser) FlatKeys(prefix ...string) []string {
+	keys := p.keysByLine()
+	if len(prefix) == 0 || prefix[0] == "" {
+		return keys
+	}
+
+	sub := keys[:0]
+	for _, key := range keys {
+		if _, ok := trimKeyPrefix(key, prefix[0]); ok {
+			sub = append(sub, key)
+		}
+	}
+	return sub
+}
+
+// HasPrefix check has keys under the prefix. eg: HasPrefix("spring.redis")
+func (p *Parser) HasPrefix(prefix string) bool {
+	for key := range p.smap {
+		if _, ok := trimKeyPrefix(key, prefix); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// WithPrefix re-root all keys under the prefix, it is useful for merge the data to a sub-tree. eg:
+//
+//	// "host" -> "spring.redis.host"
+//	err := Merge(dst, src.WithPrefix("spring.redis"), MergeOverride)
+func (p *Parser) WithPrefix(prefix string) *Parser {
+	if prefix = strings.Trim(prefix, "."); prefix == "" {
+		return p
+	}
+
+	return p.reset(p.rekey(func(key string) (string, bool) {
+		return prefix + "." + key, true
+	}))
+}
+
+// StripPrefix re-root the parser to the sub-tree of the prefix, the keys not under the prefix will be removed.
+//
+// It is the in-place version of Scope(), see WithPrefix for the reverse operation.
+func (p *Parser) StripPrefix(prefix string) *Parser {
+	if prefix = strings.Trim(prefix, "."); prefix == "" {
+		return p
+	}
+	return p.reset(p.Scope(prefix))
+}
+
+// rekey copy the parser data by convert the keys, the key will be skipped on fn returns false.
+func (p *Parser) rekey(fn func(key string) (string, bool)) *Parser {
+	opts := *p.opts
+	np := &Parser{
+		opts: &opts,
+		smap: make(maputil.SMap),
+		Data: make(maputil.Data),
+		// comments map
+		comments: make(map[string]string),
+		entries:  make(map[string]*Entry),
+	}
+
+	for _, key := range p.keysByLine() {
+		newKey, ok := fn(key)
+		if !ok {
+			continue
+		}
+
+		val, ok := maputil.GetByPath(pathOfKey(key), p.Data)
+		if !ok {
+			continue
+		}
+
+		if e, ok := p.entries[key]; ok {
+			ne := *e
+			ne.Key = newKey
+			np.entries[newKey] = &ne
+		}
+		if cmt, ok := p.comments[key]; ok {
+			np.comments[newKey] = cmt
+		}
+		np.smap[newKey] = p.smap[key]
+		np.collect(newKey, cloneValue(val))
+	}
+	return np
+}
+
+// reset the parser data by the other parser.
+func (p *Parser) reset(np *Parser) *Parser {
+	p.Data = np.Data
+	p.smap = np.smap
+	p.comments = np.comments
+	p.entries = np.entries
+	p.overridden = nil
+	return p
+}
+
+// keys of the SMap, sorted by the line number then key name. the keys without line are at the end.
+func (p *Parser) keysByLine() []string {
+	keys := p.smap.Keys()
+	sort.Slice(keys, func(i, j int) bool {
+		li, lj := p.lineOf(keys[i]), p.lineOf(keys[j])
+		if li != lj {
+			return lj == 0 || li != 0 && li < lj
+		}
+		return keys[i] < keys[j]
+	})
+	return keys
+}
+
+// trim the prefix of the key, the key must be under the prefix. eg: "a.b.c" - "a.b" = "c"
+func trimKeyPrefix(key, prefix string) (string, bool) {
+	if prefix == "" {
+		return key, true
+	}
+
+	ln := len(prefix)
+	if len(key) <= ln+1 || !strings.HasPrefix(key, prefix) {
+		return "", false
+	}
+
+	switch key[ln] {
+	case '.':
+		return key[ln+1:], true
+	case '[':
+		return key[ln:], true
+	}
+	return "", false
+}
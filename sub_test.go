@@ -0,0 +1,107 @@
+package properties_test
+
+import (
+	"testing"
+
+	"github.com/gookit/goutil/testutil/assert"
+	"github.com/gookit/properties"
+)
+
+var subText = `
+# redis host
+spring.redis.host = localhost
+spring.redis.port = 6379
+spring.redis.password = "pwd234"
+spring.datasource.url = jdbc:mysql://localhost/db
+servers[0].addr = 127.0.0.1
+servers[1].addr = 127.0.0.2
+tags[0] = a
+tags[1] = b
+`
+
+func TestParser_Scope(t *testing.T) {
+	p, err := properties.Parse(subText)
+	assert.NoErr(t, err)
+
+	redis := p.Scope("spring.redis")
+	assert.Eq(t, "localhost", redis.Str("host"))
+	assert.Eq(t, 6379, redis.Int("port"))
+	assert.Eq(t, []string{"host", "port", "password"}, redis.FlatKeys())
+	assert.Eq(t, "6379", redis.SMap()["port"])
+	assert.Eq(t, "# redis host", redis.Comments()["host"])
+
+	e, ok := redis.Entry("password")
+	assert.True(t, ok)
+	assert.Eq(t, "password", e.Key)
+	assert.Eq(t, properties.QuoteDouble, e.Quote)
+	assert.Eq(t, 5, e.Line)
+
+	bs, err := properties.Encode(redis)
+	assert.NoErr(t, err)
+	assert.Eq(t, "host=localhost\npassword=\"pwd234\"\nport=6379\n", string(bs))
+
+	// modify sub will not affect the parent
+	redis.Set("host", "127.0.0.1")
+	assert.Eq(t, "localhost", p.Str("spring.redis.host"))
+
+	// slice item
+	srv := p.Scope("servers[1]")
+	assert.Eq(t, "127.0.0.2", srv.Str("addr"))
+	assert.Len(t, p.Scope("tags").SMap(), 0)
+	assert.Len(t, p.Scope("not-exists").Data, 0)
+
+	// the Sub of maputil.Data
+	assert.Eq(t, "localhost", p.Sub("spring").Get("redis.host"))
+
+	var cfg struct {
+		Host string
+		Port int
+	}
+	assert.NoErr(t, redis.Decode(&cfg))
+	assert.Eq(t, 6379, cfg.Port)
+}
+
+func TestParser_Keys_HasPrefix(t *testing.T) {
+	p, err := properties.Parse(subText)
+	assert.NoErr(t, err)
+
+	assert.Len(t, p.FlatKeys(), 8)
+	// top-level keys of the Data
+	assert.Len(t, p.Keys(), 3)
+	assert.Eq(t, []string{"spring.redis.host", "spring.redis.port", "spring.redis.password"}, p.FlatKeys("spring.redis"))
+	assert.Eq(t, []string{"tags[0]", "tags[1]"}, p.FlatKeys("tags"))
+	assert.Len(t, p.FlatKeys("spring.red"), 0)
+
+	assert.True(t, p.HasPrefix("spring"))
+	assert.True(t, p.HasPrefix("servers"))
+	assert.False(t, p.HasPrefix("spring.red"))
+	assert.False(t, p.HasPrefix("spring.redis.host"))
+}
+
+func TestParser_WithPrefix_StripPrefix(t *testing.T) {
+	p, err := properties.Parse("host = localhost\nport = 6379\nids[0] = 1\n")
+	assert.NoErr(t, err)
+
+	p.WithPrefix("spring.redis")
+	assert.Eq(t, "localhost", p.Str("spring.redis.host"))
+	assert.Eq(t, "1", p.Str("spring.redis.ids.0"))
+	assert.Eq(t, []string{"spring.redis.host", "spring.redis.port", "spring.redis.ids[0]"}, p.FlatKeys())
+	assert.False(t, p.Has("host"))
+
+	// merge to the sub-tree
+	dst, err := properties.Parse(subText)
+	assert.NoErr(t, err)
+	src, err := properties.Parse("port = 6380\n")
+	assert.NoErr(t, err)
+	assert.NoErr(t, properties.Merge(dst, src.WithPrefix("spring.redis"), properties.MergeOverride))
+	assert.Eq(t, 6380, dst.Int("spring.redis.port"))
+	assert.Eq(t, "localhost", dst.Str("spring.redis.host"))
+
+	p.StripPrefix("spring")
+	assert.Eq(t, []string{"redis.host", "redis.port", "redis.ids[0]"}, p.FlatKeys())
+	assert.Eq(t, "6379", p.Str("redis.port"))
+
+	// empty prefix
+	assert.Eq(t, p, p.WithPrefix("").StripPrefix("."))
+	assert.Len(t, p.FlatKeys(), 3)
+}
@@ -0,0 +1,37 @@
+// Package tomlconv convert between the properties and TOML.
+//
+// It is a separate package for the core package not depend on the TOML library.
+package tomlconv
+
+import (
+	"bytes"
+
+	"github.com/BurntSushi/toml"
+	"github.com/gookit/properties"
+)
+
+// ToTOML convert the parsed data to TOML. nested maps will be as tables.
+//
+// NOTE: the number and bool string will be written as TOML number and bool value.
+// see properties.Parser.TypedData
+func ToTOML(p *properties.Parser) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := toml.NewEncoder(&buf)
+	enc.Indent = ""
+
+	if err := enc.Encode(p.TypedData()); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// FromTOML convert TOML to properties Parser. tables will be as key prefix.
+//
+// eg: "[db]\nurl = 'jdbc'" -> "db.url=jdbc"
+func FromTOML(bs []byte, optFns ...properties.OpFunc) (*properties.Parser, error) {
+	data := make(map[string]any)
+	if _, err := toml.NewDecoder(bytes.NewReader(bs)).Decode(&data); err != nil {
+		return nil, err
+	}
+	return properties.FromMap(data, optFns...)
+}
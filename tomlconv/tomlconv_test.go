@@ -0,0 +1,131 @@
+package tomlconv_test
+
+import (
+	"testing"
+
+	"github.com/gookit/goutil/testutil/assert"
+	"github.com/gookit/properties"
+	"github.com/gookit/properties/tomlconv"
+)
+
+var convertText = `
+name = inhere
+port = 8080
+debug = true
+desc = "some \"desc\" # not comment"
+db.url = jdbc:mysql://localhost/db
+db.pool.max-idle = 8
+tags[0] = a
+tags[1] = b c
+users[0].name = tom
+users[0].age = 23
+users[1].name = "jerry: mouse"
+`
+
+func mustParse(t *testing.T, text string) *properties.Parser {
+	p, err := properties.Parse(text)
+	assert.NoErr(t, err)
+	return p
+}
+
+// check the converted data is same as the source
+func assertSameData(t *testing.T, src, dst *properties.Parser) {
+	dr := properties.Diff(src, dst)
+	assert.True(t, dr.IsEmpty(), dr.String())
+}
+
+func TestTOML(t *testing.T) {
+	p := mustParse(t, convertText)
+
+	bs, err := tomlconv.ToTOML(p)
+	assert.NoErr(t, err)
+	want := `debug = true
+desc = "some \"desc\" # not comment"
+name = "inhere"
+port = 8080
+tags = ["a", "b c"]
+
+[db]
+url = "jdbc:mysql://localhost/db"
+[db.pool]
+max-idle = 8
+
+[[users]]
+age = 23
+name = "tom"
+
+[[users]]
+name = "jerry: mouse"
+`
+	assert.Eq(t, want, string(bs))
+
+	p2, err := tomlconv.FromTOML(bs)
+	assert.NoErr(t, err)
+	assertSameData(t, p, p2)
+}
+
+func TestFromTOML(t *testing.T) {
+	text := `
+# comments
+title = "TOML \u0041 example"
+path = 'C:\Users\inhere' # literal
+num = 1_000
+hex = 0xff
+pi = 3.14
+dob = 1979-05-27 07:32:00Z
+day = 1979-05-27
+"quoted key" = "v"
+site."google.com" = true
+desc = """
+line1 \
+  line2"""
+ports = [
+  8001, # comments
+  8002,
+]
+inline = { name = "tom", age = 23 }
+
+[servers.alpha]
+ip = "10.0.0.1"
+
+[[products]]
+name = "Hammer"
+
+[[products]]
+name = "Nail"
+`
+	p, err := tomlconv.FromTOML([]byte(text))
+	assert.NoErr(t, err)
+
+	smp := p.SMap()
+	assert.Eq(t, "TOML A example", smp["title"])
+	assert.Eq(t, `C:\Users\inhere`, smp["path"])
+	assert.Eq(t, "1000", smp["num"])
+	assert.Eq(t, "255", smp["hex"])
+	assert.Eq(t, "3.14", smp["pi"])
+	assert.Eq(t, "1979-05-27T07:32:00Z", smp["dob"])
+	assert.Eq(t, "1979-05-27", smp["day"])
+	assert.Eq(t, "v", smp["quoted key"])
+	assert.Eq(t, "true", smp["site.google.com"])
+	assert.Eq(t, "line1 line2", smp["desc"])
+	assert.Eq(t, "8002", smp["ports[1]"])
+	assert.Eq(t, "tom", smp["inline.name"])
+	assert.Eq(t, "10.0.0.1", smp["servers.alpha.ip"])
+	assert.Eq(t, "Nail", smp["products[1].name"])
+
+	_, err = tomlconv.FromTOML([]byte("a = "))
+	assert.ErrSubMsg(t, err, "toml: line 1")
+	_, err = tomlconv.FromTOML([]byte("a = 1 b = 2"))
+	assert.Err(t, err)
+	_, err = tomlconv.FromTOML([]byte("[a\nb = 1"))
+	assert.Err(t, err)
+	_, err = tomlconv.FromTOML([]byte(`a = "abc`))
+	assert.Err(t, err)
+}
+
+func TestToTOML_quoted(t *testing.T) {
+	p := mustParse(t, "flag = \"true\"\nport = '8080'\nnum = 8080\n")
+	bs, err := tomlconv.ToTOML(p)
+	assert.NoErr(t, err)
+	assert.Eq(t, "flag = \"true\"\nnum = 8080\nport = \"8080\"\n", string(bs))
+}
@@ -1,42 +1,11 @@
 package properties
 
 import (
-	"reflect"
 	"regexp"
+	"strconv"
 	"strings"
-	"time"
-
-	"github.com/go-viper/mapstructure/v2"
-	"github.com/gookit/goutil/strutil"
 )
 
-// ValDecodeHookFunc returns a mapstructure.DecodeHookFunc that parse time string
-func ValDecodeHookFunc() mapstructure.DecodeHookFunc {
-	return func(f reflect.Type, t reflect.Type, data any) (any, error) {
-		if f.Kind() != reflect.String {
-			return data, nil
-		}
-
-		str := data.(string)
-		ln := len(str)
-		if ln < 2 {
-			return str, nil
-		}
-
-		// start char is number(1-9) and end char is a-z.
-		if str[0] > '0' && str[0] < '9' && str[ln-1] > 'a' {
-			// parse time string. eg: 10s
-			if t.Kind() == reflect.Int64 {
-				dur, err := time.ParseDuration(str)
-				if err == nil {
-					return dur, nil
-				}
-			}
-		}
-		return str, nil
-	}
-}
-
 // eg: ${some.other.key} -> some.other.key
 var refRegex = regexp.MustCompile(`^[a-z][a-z\d.]+$`)
 
@@ -52,10 +21,37 @@ func parseVarRefName(val string) (string, bool) {
 	return "", false
 }
 
-func parseInlineSlice(s string, ln int) (ss []string, ok bool) {
-	// eg: [34, 56]
-	if s[0] == '[' && s[ln-1] == ']' {
-		return strutil.Split(s[1:ln-1], ","), true
+// eg: 1.5, -.5, 1e3, 2.5E-3
+var floatRegex = regexp.MustCompile(`^[-+]?(\d+\.\d*|\.\d+|\d+)([eE][-+]?\d+)?$`)
+
+// inferValue infer type of the string value. eg: "123" -> int64(123)
+func inferValue(s string) any {
+	ln := len(s)
+	if ln == 0 {
+		return s
+	}
+
+	switch s {
+	case "null", "NULL", "Null":
+		return nil
+	case "true", "TRUE", "True":
+		return true
+	case "false", "FALSE", "False":
+		return false
+	}
+
+	// start with 0 and not a float, keep as string. eg: 007, 0755
+	if ln > 1 && s[0] == '0' && s[1] != '.' {
+		return s
+	}
+
+	if iv, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return iv
+	}
+	if floatRegex.MatchString(s) {
+		if fv, err := strconv.ParseFloat(s, 64); err == nil {
+			return fv
+		}
 	}
-	return
+	return s
 }
@@ -0,0 +1,354 @@
+package properties
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gookit/goutil/reflects"
+	"github.com/gookit/goutil/strutil"
+)
+
+// Validator for validate the struct after Parser.MapStruct(). see Options.Validator
+//
+// Return FieldErrors for report the errors with the properties key and line.
+type Validator interface {
+	Validate(ptr any) error
+}
+
+// ValidatorFunc wrap a func as the Validator
+type ValidatorFunc func(ptr any) error
+
+// Validate the struct
+func (fn ValidatorFunc) Validate(ptr any) error {
+	return fn(ptr)
+}
+
+// FieldError a validation error of the struct field
+type FieldError struct {
+	// Field path of the struct. eg: Port, DB.Host, Servers[0].Addr, Labels[env]
+	Field string
+	// Message of the error
+	Message string
+}
+
+// Error string
+func (e *FieldError) Error() string {
+	return e.Field + ": " + e.Message
+}
+
+// FieldErrors multi field errors
+type FieldErrors []*FieldError
+
+// Error string, one error per line.
+func (es FieldErrors) Error() string {
+	ss := make([]string, len(es))
+	for i, e := range es {
+		ss[i] = e.Error()
+	}
+	return strings.Join(ss, "\n")
+}
+
+// TagValidator the builtin validator by the struct tag. eg: `validate:"required,min=1,max=65535"`
+//
+// Rules:
+//
+//	required   the value must be not zero
+//	min=N      min value of the number, or min length of string, slice and map
+//	max=N      max value of the number, or max length of string, slice and map
+//	len=N      the length of string, slice and map
+//	oneof=a b  the value must be one of the space separated values
+//	regex=expr the string value must match the regexp, should not contain the comma.
+//
+// NOTE: for time.Duration field, the N of min, max can be a duration string. eg: min=1s
+type TagValidator struct {
+	// TagName for the rules. default: ValidateTagName
+	TagName string
+}
+
+// NewTagValidator instance
+func NewTagValidator() *TagValidator {
+	return &TagValidator{TagName: ValidateTagName}
+}
+
+// Validate the struct ptr, returns FieldErrors on has invalid fields.
+func (v *TagValidator) Validate(ptr any) error {
+	rv := reflect.Indirect(reflect.ValueOf(ptr))
+	if rv.Kind() != reflect.Struct {
+		return errors.New("validate: must be provide a struct or struct ptr")
+	}
+
+	var es FieldErrors
+	if err := v.validateStruct(rv, "", &es); err != nil {
+		return err
+	}
+
+	if len(es) > 0 {
+		return es
+	}
+	return nil
+}
+
+func (v *TagValidator) validateStruct(rv reflect.Value, parent string, es *FieldErrors) error {
+	tagName := v.TagName
+	if tagName == "" {
+		tagName = ValidateTagName
+	}
+
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		sf := rt.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+
+		path := sf.Name
+		if parent != "" {
+			path = parent + "." + sf.Name
+		}
+
+		fv := rv.Field(i)
+		for _, rule := range parseRuleTag(sf.Tag.Get(tagName)) {
+			msg, err := checkRule(fv, rule)
+			if err != nil {
+				return fmt.Errorf("validate: invalid rule %q of the field %s: %w", rule.name, path, err)
+			}
+			if msg != "" {
+				*es = append(*es, &FieldError{Field: path, Message: msg})
+				// only report the first error of the field
+				break
+			}
+		}
+
+		if err := v.validateNested(fv, path, es); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validate the nested struct, slice and map of the struct.
+func (v *TagValidator) validateNested(fv reflect.Value, path string, es *FieldErrors) error {
+	fv = reflects.Indirect(fv)
+	switch fv.Kind() {
+	case reflect.Struct:
+		if isNestedType(fv.Type()) {
+			return v.validateStruct(fv, path, es)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < fv.Len(); i++ {
+			if err := v.validateNested(fv.Index(i), path+"["+strconv.Itoa(i)+"]", es); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		iter := fv.MapRange()
+		for iter.Next() {
+			if err := v.validateNested(iter.Value(), path+"["+fmt.Sprint(iter.Key().Interface())+"]", es); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// check the field value by rule, returns error message on invalid.
+func checkRule(fv reflect.Value, rule tagRule) (string, error) {
+	if rule.name == "required" {
+		if fv.IsZero() {
+			return "is required", nil
+		}
+		return "", nil
+	}
+
+	if fv.Kind() == reflect.Ptr {
+		if fv.IsNil() {
+			return "", nil
+		}
+		fv = fv.Elem()
+	}
+
+	switch rule.name {
+	case "min", "max", "len":
+		num, name, ok := sizeOf(fv)
+		if !ok {
+			return "", fmt.Errorf("not support the type %s", fv.Type())
+		}
+
+		var limit float64
+		if fv.Type() == durationType {
+			dur, err := time.ParseDuration(rule.arg)
+			if err != nil {
+				return "", err
+			}
+			limit = float64(dur)
+		} else {
+			var err error
+			if limit, err = strconv.ParseFloat(rule.arg, 64); err != nil {
+				return "", err
+			}
+		}
+
+		switch {
+		case rule.name == "min" && num < limit:
+			return fmt.Sprintf("%s must be >= %s", name, rule.arg), nil
+		case rule.name == "max" && num > limit:
+			return fmt.Sprintf("%s must be <= %s", name, rule.arg), nil
+		case rule.name == "len" && num != limit:
+			return fmt.Sprintf("%s must be %s", name, rule.arg), nil
+		}
+	case "oneof":
+		val := fmt.Sprint(fv.Interface())
+		if enum := strings.Fields(rule.arg); !strutil.InArray(val, enum) {
+			return fmt.Sprintf("value %q must be one of [%s]", val, strings.Join(enum, ", ")), nil
+		}
+	case "regex":
+		if fv.Kind() != reflect.String {
+			return "", fmt.Errorf("not support the type %s", fv.Type())
+		}
+
+		re, err := regexp.Compile(rule.arg)
+		if err != nil {
+			return "", err
+		}
+		if !re.MatchString(fv.String()) {
+			return fmt.Sprintf("value %q must match the pattern %s", fv.String(), rule.arg), nil
+		}
+	default:
+		return "", errors.New("unknown rule")
+	}
+	return "", nil
+}
+
+// number value or length of the value for compare.
+func sizeOf(fv reflect.Value) (num float64, name string, ok bool) {
+	switch fv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(fv.Int()), "value", true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(fv.Uint()), "value", true
+	case reflect.Float32, reflect.Float64:
+		return fv.Float(), "value", true
+	case reflect.String, reflect.Slice, reflect.Array, reflect.Map:
+		return float64(fv.Len()), "length", true
+	}
+	return 0, "", false
+}
+
+// validate the struct after mapping, convert the FieldErrors to KeyErrors.
+func (p *Parser) validate(key string, ptr any) error {
+	err := p.opts.Validator.Validate(ptr)
+
+	var fes FieldErrors
+	if err == nil || !errors.As(err, &fes) {
+		return err
+	}
+
+	rt := reflect.TypeOf(ptr)
+	es := make(KeyErrors, 0, len(fes))
+	for _, fe := range fes {
+		fKey := p.sourceKey(p.fieldKey(rt, key, fe.Field))
+		es = append(es, &KeyError{Key: fKey, Line: p.lineOf(fKey), Message: fe.Message})
+	}
+	return es
+}
+
+// fieldKey convert the field path to the properties key. eg: "DB.MaxWait" -> "db.max-wait"
+//
+// returns the field path on the field not found.
+func (p *Parser) fieldKey(rt reflect.Type, parent, path string) string {
+	key := parent
+	for _, node := range strings.Split(path, ".") {
+		name, index, _ := strings.Cut(node, "[")
+
+		for rt.Kind() == reflect.Ptr {
+			rt = rt.Elem()
+		}
+		if rt.Kind() != reflect.Struct {
+			return path
+		}
+
+		sf, keyName, ok := p.findField(rt, name)
+		if !ok {
+			return path
+		}
+
+		if keyName != "" {
+			if key != "" {
+				key += "."
+			}
+			key += keyName
+		}
+
+		rt = sf.Type
+		// index of slice or key of map. eg: "Servers[0]", "Labels[env]"
+		for index != "" {
+			idx, rest, _ := strings.Cut(index, "]")
+			index = strings.TrimPrefix(rest, "[")
+
+			for rt.Kind() == reflect.Ptr {
+				rt = rt.Elem()
+			}
+			switch rt.Kind() {
+			case reflect.Map:
+				key += "." + idx
+			case reflect.Slice, reflect.Array:
+				key += "[" + idx + "]"
+			default:
+				return path
+			}
+			rt = rt.Elem()
+		}
+	}
+	return key
+}
+
+// find the field by Go name, returns the key name of the field. keyName is empty for squash field.
+func (p *Parser) findField(rt reflect.Type, name string) (sf reflect.StructField, keyName string, ok bool) {
+	sf, ok = rt.FieldByName(name)
+	if !ok {
+		return sf, "", false
+	}
+
+	keyName, opts, _ := strings.Cut(sf.Tag.Get(p.opts.tagName()), ",")
+	if sf.Anonymous && strings.Contains(opts, "squash") {
+		return sf, "", true
+	}
+
+	if keyName == "" {
+		keyName = sf.Name
+		if p.opts.NameStrategy != nil {
+			keyName = p.opts.NameStrategy(sf.Name)
+		}
+	}
+	return sf, keyName, true
+}
+
+// sourceKey find the parsed key by relaxed match. eg: "db.maxWait" -> "db.max-wait"
+//
+// for comma separated list value, "tags[1]" -> "tags"
+func (p *Parser) sourceKey(key string) string {
+	for key != "" {
+		if _, ok := p.entries[key]; ok {
+			return key
+		}
+
+		rk := RelaxedKey(key)
+		for k := range p.entries {
+			if RelaxedKey(k) == rk {
+				return k
+			}
+		}
+
+		pos := strings.LastIndexByte(key, '[')
+		if pos < 1 || !strings.HasSuffix(key, "]") {
+			break
+		}
+		key = key[:pos]
+	}
+	return key
+}
@@ -0,0 +1,150 @@
+package properties_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gookit/goutil/testutil/assert"
+	"github.com/gookit/properties"
+)
+
+type ValidateBase struct {
+	Env string `properties:"env" validate:"oneof=dev test prod"`
+}
+
+type validateConf struct {
+	ValidateBase `properties:",squash"`
+
+	Name    string        `properties:"name" validate:"required"`
+	Port    int           `properties:"port" validate:"min=1,max=65535"`
+	Timeout time.Duration `properties:"timeout" validate:"min=1s"`
+	Tags    []string      `properties:"tags" validate:"max=2"`
+	DB      struct {
+		MaxWait int    `validate:"max=100"`
+		Host    string `properties:"host" validate:"regex=^[a-z.]+$"`
+	} `properties:"db"`
+	Servers []struct {
+		Addr string `properties:"addr" validate:"required,len=9"`
+	} `properties:"servers"`
+	Labels map[string]*struct {
+		Value string `properties:"value" validate:"required"`
+	} `properties:"labels"`
+}
+
+func TestParser_MapStruct_validate(t *testing.T) {
+	text := `name = inhere
+port = 8080
+env = dev
+timeout = 3s
+db.max-wait = 50
+db.host = localhost
+servers[0].addr = 127.0.0.1
+`
+
+	p := properties.NewParser(properties.ValidateByTag, properties.WithNameStrategy(properties.KebabCase))
+	assert.NoErr(t, p.Parse(text))
+
+	cfg := &validateConf{}
+	assert.NoErr(t, p.Decode(cfg))
+	assert.Eq(t, 8080, cfg.Port)
+
+	text = `name =
+port = 70000
+env = local
+timeout = 500ms
+tags = a,b,c
+db.max-wait = 150
+db.host = Local_Host
+servers[0].addr = 127.0.0.1
+servers[1].addr = local
+labels.app.value =
+`
+
+	p = properties.NewParser(properties.ValidateByTag, properties.WithNameStrategy(properties.KebabCase))
+	assert.NoErr(t, p.Parse(text))
+
+	err := p.Decode(&validateConf{})
+	assert.Err(t, err)
+
+	var es properties.KeyErrors
+	assert.True(t, errors.As(err, &es))
+	assert.Eq(t, `line 3: key "env": value "local" must be one of [dev, test, prod]
+line 1: key "name": is required
+line 2: key "port": value must be <= 65535
+line 4: key "timeout": value must be >= 1s
+line 5: key "tags": length must be <= 2
+line 6: key "db.max-wait": value must be <= 100
+line 7: key "db.host": value "Local_Host" must match the pattern ^[a-z.]+$
+line 9: key "servers[1].addr": length must be 9
+line 10: key "labels.app.value": is required`, err.Error())
+}
+
+func TestParser_MapStruct_validator(t *testing.T) {
+	type conf struct {
+		Server struct {
+			MaxConn int
+		} `properties:"server"`
+	}
+
+	v := properties.ValidatorFunc(func(ptr any) error {
+		var maxConn int
+		switch typVal := ptr.(type) {
+		case *conf:
+			maxConn = typVal.Server.MaxConn
+		case *struct{ MaxConn int }:
+			maxConn = typVal.MaxConn
+		}
+
+		if maxConn > 10 {
+			field := "MaxConn"
+			if _, ok := ptr.(*conf); ok {
+				field = "Server.MaxConn"
+			}
+			return properties.FieldErrors{{Field: field, Message: "too many connections"}}
+		}
+		return nil
+	})
+
+	// relaxed binding
+	p := properties.NewParser(properties.WithValidator(v), properties.RelaxedBinding)
+	assert.NoErr(t, p.Parse("# comments\nserver.max-conn = 20\n"))
+
+	err := p.Decode(&conf{})
+	assert.Eq(t, `line 2: key "server.max-conn": too many connections`, err.Error())
+
+	// sub key
+	sub := &struct{ MaxConn int }{}
+	err = p.MapStruct("server", sub)
+	assert.Eq(t, `line 2: key "server.max-conn": too many connections`, err.Error())
+
+	// other error
+	p = properties.NewParser(properties.WithValidator(properties.ValidatorFunc(func(ptr any) error {
+		return errors.New("custom error")
+	})))
+	assert.NoErr(t, p.Parse("server.max-conn = 20\n"))
+	assert.ErrMsg(t, p.Decode(&conf{}), "custom error")
+}
+
+func TestTagValidator(t *testing.T) {
+	v := properties.NewTagValidator()
+	assert.Err(t, v.Validate("invalid"))
+
+	err := v.Validate(&struct {
+		Name string `validate:"unknown"`
+	}{})
+	assert.ErrMsg(t, err, `validate: invalid rule "unknown" of the field Name: unknown rule`)
+
+	err = v.Validate(&struct {
+		Age  *int `validate:"min=1"`
+		Name string
+	}{})
+	assert.NoErr(t, err)
+
+	err = v.Validate(struct {
+		Age int `validate:"min=18"`
+	}{Age: 3})
+	var es properties.FieldErrors
+	assert.True(t, errors.As(err, &es))
+	assert.Eq(t, "Age: value must be >= 18", es.Error())
+}
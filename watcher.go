@@ -0,0 +1,222 @@
+package properties
+
+import (
+	"errors"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultWatchInterval for poll the file changes
+var DefaultWatchInterval = 2 * time.Second
+
+// ChangeEvent of the watched files reloaded
+type ChangeEvent struct {
+	// Changes of the keys, sorted by key.
+	Changes []Change
+	// Old, New snapshot of the config
+	Old, New *Parser
+}
+
+// Watcher watch the properties files by polling, reload and swap the snapshot on changed.
+//
+// Files are loaded as layers by order, the later file will override the earlier.
+// On failed reload, will keep the last good snapshot. eg:
+//
+//	w, err := properties.NewWatcher([]string{"app.properties", "app-prod.properties"})
+//	w.Subscribe(func(ev *properties.ChangeEvent) {
+//		// handle changes
+//	})
+//	w.Start()
+//	defer w.Stop()
+//
+//	p := w.Current()
+type Watcher struct {
+	files []string
+	opts  []OpFunc
+	// current snapshot
+	current atomic.Pointer[Parser]
+
+	// serialize the Check and Reload, keep the snapshots and events in order
+	reloadMu sync.Mutex
+
+	mu   sync.Mutex
+	subs []func(ev *ChangeEvent)
+	// last modify time and size of the files
+	stats map[string]fileStat
+	// stop polling
+	stop chan struct{}
+
+	// Interval for poll the file changes. default: DefaultWatchInterval
+	Interval time.Duration
+	// OnError handle the reload error. eg: parse error
+	OnError func(err error)
+}
+
+type fileStat struct {
+	modTime time.Time
+	size    int64
+}
+
+// NewWatcher create a watcher and load the files. optFns will be used for parse the files.
+func NewWatcher(files []string, optFns ...OpFunc) (*Watcher, error) {
+	if len(files) == 0 {
+		return nil, errors.New("must be provide files for watch")
+	}
+
+	w := &Watcher{
+		files:    files,
+		opts:     optFns,
+		Interval: DefaultWatchInterval,
+	}
+
+	w.stats = w.statFiles()
+	p, err := w.load()
+	if err != nil {
+		return nil, err
+	}
+
+	w.current.Store(p)
+	return w, nil
+}
+
+// Current get the current config snapshot. NOTE: should not modify the returned Parser.
+func (w *Watcher) Current() *Parser {
+	return w.current.Load()
+}
+
+// Subscribe the change events
+func (w *Watcher) Subscribe(fn func(ev *ChangeEvent)) {
+	w.mu.Lock()
+	w.subs = append(w.subs, fn)
+	w.mu.Unlock()
+}
+
+// Start polling the file changes in background.
+func (w *Watcher) Start() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.stop != nil {
+		return
+	}
+
+	interval := w.Interval
+	if interval <= 0 {
+		interval = DefaultWatchInterval
+	}
+
+	stop := make(chan struct{})
+	w.stop = stop
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				if _, err := w.Check(); err != nil && w.OnError != nil {
+					w.OnError(err)
+				}
+			}
+		}
+	}()
+}
+
+// Stop polling the file changes.
+func (w *Watcher) Stop() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.stop != nil {
+		close(w.stop)
+		w.stop = nil
+	}
+}
+
+// Check the files are modified, will reload on modified. returns true on reloaded.
+func (w *Watcher) Check() (bool, error) {
+	w.reloadMu.Lock()
+	defer w.reloadMu.Unlock()
+
+	stats := w.statFiles()
+
+	w.mu.Lock()
+	modified := len(stats) != len(w.stats)
+	for file, st := range stats {
+		if old, ok := w.stats[file]; !ok || old != st {
+			modified = true
+		}
+	}
+	// record stats even reload failed, will retry on modified again.
+	w.stats = stats
+	w.mu.Unlock()
+
+	if !modified {
+		return false, nil
+	}
+	return true, w.reload()
+}
+
+// Reload the files and swap the snapshot, will keep the last good snapshot on error.
+//
+// Will notify the subscribers on the keys changed.
+// NOTE: the subscribers should not call Check or Reload, it will be blocked.
+func (w *Watcher) Reload() error {
+	w.reloadMu.Lock()
+	defer w.reloadMu.Unlock()
+	return w.reload()
+}
+
+func (w *Watcher) reload() error {
+	p, err := w.load()
+	if err != nil {
+		return err
+	}
+
+	old := w.current.Swap(p)
+	changes := diffParsers(old, p)
+	if len(changes) == 0 {
+		return nil
+	}
+
+	w.mu.Lock()
+	subs := make([]func(ev *ChangeEvent), len(w.subs))
+	copy(subs, w.subs)
+	w.mu.Unlock()
+
+	ev := &ChangeEvent{Changes: changes, Old: old, New: p}
+	for _, fn := range subs {
+		fn(ev)
+	}
+	return nil
+}
+
+func (w *Watcher) load() (*Parser, error) {
+	l := NewLayered(w.opts...)
+	for _, file := range w.files {
+		if err := l.LoadFile(file); err != nil {
+			return nil, err
+		}
+	}
+
+	// overlay env vars after all files loaded
+	if prefix := l.p.opts.EnvPrefix; prefix != "" {
+		if err := l.LoadEnv(prefix); err != nil {
+			return nil, err
+		}
+	}
+	return l.Parser(), nil
+}
+
+func (w *Watcher) statFiles() map[string]fileStat {
+	stats := make(map[string]fileStat, len(w.files))
+	for _, file := range w.files {
+		if fi, err := os.Stat(file); err == nil {
+			stats[file] = fileStat{modTime: fi.ModTime(), size: fi.Size()}
+		}
+	}
+	return stats
+}
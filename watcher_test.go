@@ -0,0 +1,151 @@
+package properties_test
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gookit/goutil/testutil/assert"
+	"github.com/gookit/properties"
+)
+
+func writeFile(t *testing.T, file, text string, mtime time.Time) {
+	assert.NoErr(t, os.WriteFile(file, []byte(text), 0644))
+	assert.NoErr(t, os.Chtimes(file, mtime, mtime))
+}
+
+func TestWatcher(t *testing.T) {
+	dir := t.TempDir()
+	file1 := filepath.Join(dir, "app.properties")
+	file2 := filepath.Join(dir, "app-prod.properties")
+
+	now := time.Now()
+	writeFile(t, file1, "name = demo\nport = 8080\ndebug = true", now)
+	writeFile(t, file2, "port = 9090", now)
+
+	_, err := properties.NewWatcher(nil)
+	assert.Err(t, err)
+	_, err = properties.NewWatcher([]string{filepath.Join(dir, "not-exists.properties")})
+	assert.Err(t, err)
+
+	w, err := properties.NewWatcher([]string{file1, file2}, properties.ParseInlineSlice)
+	assert.NoErr(t, err)
+	assert.Eq(t, "9090", w.Current().Str("port"))
+
+	var events []*properties.ChangeEvent
+	w.Subscribe(func(ev *properties.ChangeEvent) {
+		events = append(events, ev)
+	})
+
+	// not modified
+	ok, err := w.Check()
+	assert.NoErr(t, err)
+	assert.False(t, ok)
+
+	// modified
+	writeFile(t, file1, "name = demo2\nport = 8080\nhost = localhost", now.Add(time.Second))
+	ok, err = w.Check()
+	assert.NoErr(t, err)
+	assert.True(t, ok)
+	assert.Eq(t, "demo2", w.Current().Str("name"))
+	assert.Len(t, events, 1)
+
+	ev := events[0]
+	assert.Eq(t, "demo", ev.Old.Str("name"))
+	assert.Eq(t, "demo2", ev.New.Str("name"))
+	assert.Len(t, ev.Changes, 3)
+	assert.Eq(t, "debug", ev.Changes[0].Key)
+	assert.Eq(t, properties.ChangeRemoved, ev.Changes[0].Type)
+	assert.Eq(t, "host", ev.Changes[1].Key)
+	assert.Eq(t, properties.ChangeAdded, ev.Changes[1].Type)
+	assert.Eq(t, 3, ev.Changes[1].NewLine)
+	assert.Eq(t, "name", ev.Changes[2].Key)
+	assert.Eq(t, properties.ChangeUpdated, ev.Changes[2].Type)
+	assert.Eq(t, "demo", ev.Changes[2].OldValue)
+	assert.Eq(t, "demo2", ev.Changes[2].NewValue)
+	assert.Eq(t, "changed", ev.Changes[2].Type.String())
+
+	// parse error, keep last good
+	writeFile(t, file1, "name = [a, b,", now.Add(2*time.Second))
+	ok, err = w.Check()
+	assert.Err(t, err)
+	assert.True(t, ok)
+	assert.Eq(t, "demo2", w.Current().Str("name"))
+	assert.Len(t, events, 1)
+
+	// removed file, keep last good
+	assert.NoErr(t, os.Remove(file2))
+	_, err = w.Check()
+	assert.Err(t, err)
+	assert.Eq(t, "9090", w.Current().Str("port"))
+}
+
+func TestWatcher_Start(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "app.properties")
+	now := time.Now()
+	writeFile(t, file, "name = demo", now)
+
+	w, err := properties.NewWatcher([]string{file}, properties.ParseInlineSlice)
+	assert.NoErr(t, err)
+	w.Interval = 10 * time.Millisecond
+
+	changed := make(chan *properties.ChangeEvent, 1)
+	w.Subscribe(func(ev *properties.ChangeEvent) {
+		changed <- ev
+	})
+
+	w.Start()
+	w.Start() // repeat call is ok
+	defer w.Stop()
+
+	writeFile(t, file, "name = demo2", now.Add(time.Second))
+	select {
+	case ev := <-changed:
+		assert.Len(t, ev.Changes, 1)
+		assert.Eq(t, "demo2", w.Current().Str("name"))
+	case <-time.After(2 * time.Second):
+		t.Fatal("wait change event timeout")
+	}
+}
+
+func TestWatcher_concurrent(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "app.properties")
+	now := time.Now()
+	writeFile(t, file, "name = v0", now)
+
+	w, err := properties.NewWatcher([]string{file})
+	assert.NoErr(t, err)
+
+	// events are in order: the Old of event is the New of last event
+	var evs []*properties.ChangeEvent
+	w.Subscribe(func(ev *properties.ChangeEvent) {
+		evs = append(evs, ev)
+	})
+
+	var wg sync.WaitGroup
+	for i := 1; i <= 20; i++ {
+		writeFile(t, file, "name = v"+strconv.Itoa(i), now.Add(time.Duration(i)*time.Second))
+
+		for j := 0; j < 4; j++ {
+			wg.Add(2)
+			go func() {
+				defer wg.Done()
+				_, _ = w.Check()
+			}()
+			go func() {
+				defer wg.Done()
+				_ = w.Reload()
+			}()
+		}
+	}
+	wg.Wait()
+
+	assert.NoErr(t, w.Reload())
+	assert.Eq(t, "v20", w.Current().Str("name"))
+	for i := 1; i < len(evs); i++ {
+		assert.Eq(t, evs[i-1].New, evs[i].Old)
+	}
+}
@@ -0,0 +1,56 @@
+// Package yamlconv convert between the properties and YAML, like Spring application.yml
+//
+// It is a separate package for the core package not depend on the YAML library.
+package yamlconv
+
+import (
+	"bytes"
+	"errors"
+	"io"
+
+	"github.com/gookit/properties"
+	"gopkg.in/yaml.v3"
+)
+
+// ToYAML convert the parsed data to YAML.
+//
+// eg: "db.url=jdbc" -> "db:\n  url: jdbc", "tags[0]=a" -> "tags:\n  - a"
+//
+// NOTE: the number and bool string will be written as YAML number and bool value,
+// other strings will be quoted on need. eg: "yes", "010". see properties.Parser.TypedData
+func ToYAML(p *properties.Parser) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+
+	if err := enc.Encode(p.TypedData()); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// FromYAML convert YAML to properties Parser, use Spring's flattening rules.
+//
+// eg: "db:\n  url: jdbc" -> "db.url=jdbc", "tags:\n  - a" -> "tags[0]=a"
+//
+// NOTE: the null value will be as empty string, not support multi documents.
+func FromYAML(bs []byte, optFns ...properties.OpFunc) (*properties.Parser, error) {
+	dec := yaml.NewDecoder(bytes.NewReader(bs))
+
+	data := make(map[string]any)
+	if err := dec.Decode(&data); err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	var more any
+	if err := dec.Decode(&more); err != io.EOF {
+		if err != nil {
+			return nil, err
+		}
+		return nil, errors.New("yaml: multi documents is not supported")
+	}
+	return properties.FromMap(data, optFns...)
+}
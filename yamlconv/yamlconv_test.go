@@ -0,0 +1,162 @@
+package yamlconv_test
+
+import (
+	"testing"
+
+	"github.com/gookit/goutil/testutil/assert"
+	"github.com/gookit/properties"
+	"github.com/gookit/properties/yamlconv"
+)
+
+var convertText = `
+name = inhere
+port = 8080
+debug = true
+desc = "some \"desc\" # not comment"
+db.url = jdbc:mysql://localhost/db
+db.pool.max-idle = 8
+tags[0] = a
+tags[1] = b c
+users[0].name = tom
+users[0].age = 23
+users[1].name = "jerry: mouse"
+`
+
+func mustParse(t *testing.T, text string) *properties.Parser {
+	p, err := properties.Parse(text)
+	assert.NoErr(t, err)
+	return p
+}
+
+// check the converted data is same as the source
+func assertSameData(t *testing.T, src, dst *properties.Parser) {
+	dr := properties.Diff(src, dst)
+	assert.True(t, dr.IsEmpty(), dr.String())
+}
+
+func TestYAML(t *testing.T) {
+	p := mustParse(t, convertText)
+
+	bs, err := yamlconv.ToYAML(p)
+	assert.NoErr(t, err)
+	want := `db:
+  pool:
+    max-idle: 8
+  url: jdbc:mysql://localhost/db
+debug: true
+desc: 'some "desc" # not comment'
+name: inhere
+port: 8080
+tags:
+  - a
+  - b c
+users:
+  - age: 23
+    name: tom
+  - name: 'jerry: mouse'
+`
+	assert.Eq(t, want, string(bs))
+
+	p2, err := yamlconv.FromYAML(bs)
+	assert.NoErr(t, err)
+	assertSameData(t, p, p2)
+}
+
+func TestFromYAML(t *testing.T) {
+	text := `
+# comments
+---
+spring:
+  application:
+    name: demo # inline comments
+  datasource:
+    url: 'jdbc:mysql://localhost/db'
+    password: "it's \"secret\""
+  profiles:
+  - dev
+  - prod
+servers:
+  - host: a.com
+    ports: [80, 443]
+  -
+    host: b.com
+    tags: {k1: v1, k2: v2}
+matrix:
+  - - 1
+    - 2
+  - [3, 4]
+empty:
+nothing: ~
+literal: |
+  line1
+  line2
+folded: >-
+  some long
+  text
+
+  new para
+`
+	p, err := yamlconv.FromYAML([]byte(text))
+	assert.NoErr(t, err)
+
+	smp := p.SMap()
+	assert.Eq(t, "demo", smp["spring.application.name"])
+	assert.Eq(t, "jdbc:mysql://localhost/db", smp["spring.datasource.url"])
+	assert.Eq(t, `it's "secret"`, smp["spring.datasource.password"])
+	assert.Eq(t, "dev", smp["spring.profiles[0]"])
+	assert.Eq(t, "prod", smp["spring.profiles[1]"])
+	assert.Eq(t, "a.com", smp["servers[0].host"])
+	assert.Eq(t, "443", smp["servers[0].ports[1]"])
+	assert.Eq(t, "b.com", smp["servers[1].host"])
+	assert.Eq(t, "v2", smp["servers[1].tags.k2"])
+	assert.Eq(t, "2", smp["matrix[0][1]"])
+	assert.Eq(t, "4", smp["matrix[1][1]"])
+	assert.Eq(t, "line1\nline2\n", smp["literal"])
+	assert.Eq(t, "some long text\nnew para", smp["folded"])
+	// null values as empty string
+	assert.Eq(t, "", smp["nothing"])
+	assert.Eq(t, "", smp["empty"])
+	assert.Contains(t, smp.Keys(), "nothing")
+
+	_, err = yamlconv.FromYAML([]byte("a: b\n  c: d"))
+	assert.ErrSubMsg(t, err, "yaml: line 2")
+	_, err = yamlconv.FromYAML([]byte("- a\n- b"))
+	assert.Err(t, err)
+	_, err = yamlconv.FromYAML([]byte("a: [b, c"))
+	assert.Err(t, err)
+	_, err = yamlconv.FromYAML([]byte("a: b\n---\nc: d"))
+	assert.ErrSubMsg(t, err, "multi documents")
+}
+
+func TestYAML_anchors_quote(t *testing.T) {
+	p, err := yamlconv.FromYAML([]byte(`
+base: &base
+  host: localhost
+  port: 3306
+dev:
+  <<: *base
+  port: 3307
+ref: *base
+`))
+	assert.NoErr(t, err)
+	assert.Eq(t, "localhost", p.Str("dev.host"))
+	assert.Eq(t, "3307", p.Str("dev.port"))
+	assert.Eq(t, "3306", p.Str("ref.port"))
+
+	// the YAML 1.1 bool words and leading zero numbers are kept as string
+	p = mustParse(t, "a = yes\nb = off\nc = Y\nd = 010\ne = True\nf = 10\n")
+	bs, err := yamlconv.ToYAML(p)
+	assert.NoErr(t, err)
+	assert.Eq(t, "a: \"yes\"\nb: \"off\"\nc: \"Y\"\nd: \"010\"\ne: \"True\"\nf: 10\n", string(bs))
+
+	p2, err := yamlconv.FromYAML(bs)
+	assert.NoErr(t, err)
+	assertSameData(t, p, p2)
+}
+
+func TestToYAML_quoted(t *testing.T) {
+	p := mustParse(t, "flag = \"true\"\nport = '8080'\nnum = 8080\ntags[0] = \"1\"\ntags[1] = 2\n")
+	bs, err := yamlconv.ToYAML(p)
+	assert.NoErr(t, err)
+	assert.Eq(t, "flag: \"true\"\nnum: 8080\nport: \"8080\"\ntags:\n  - \"1\"\n  - 2\n", string(bs))
+}